@@ -0,0 +1,38 @@
+#!/usr/bin/env bash
+# Backup ledger — remember files that installers move or copy aside
+#
+# Each backup is one JSON object per line in
+# ~/.local/share/mypctools/backups.jsonl:
+#   {"bundle":"kitty","original":"/home/u/.config/kitty/kitty.conf",
+#    "backup":"/home/u/.config/kitty/kitty.conf.backup.20250101_120000","time":"..."}
+# The TUI's Backups screen reads it to diff, restore, or prune backups.
+
+[[ -n "$_BACKUP_SH_LOADED" ]] && return 0
+_BACKUP_SH_LOADED=1
+
+BACKUP_LEDGER="$HOME/.local/share/mypctools/backups.jsonl"
+
+# Escape a string for use inside JSON double quotes.
+_json_escape() {
+    local s="$1"
+    s="${s//\\/\\\\}"
+    s="${s//\"/\\\"}"
+    s="${s//$'\t'/\\t}"
+    s="${s//$'\n'/\\n}"
+    printf '%s' "$s"
+}
+
+# Usage: record_backup <original> <backup>
+# The bundle is the directory of the running install script (scripts/<id>/).
+record_backup() {
+    local original="$1" backup="$2"
+    local bundle
+    bundle="$(basename "$(dirname "$(readlink -f "${BASH_SOURCE[-1]}")")")"
+
+    mkdir -p "$(dirname "$BACKUP_LEDGER")" || return 1
+    printf '{"bundle":"%s","original":"%s","backup":"%s","time":"%s"}\n' \
+        "$(_json_escape "$bundle")" \
+        "$(_json_escape "$original")" \
+        "$(_json_escape "$backup")" \
+        "$(date -Iseconds)" >> "$BACKUP_LEDGER"
+}
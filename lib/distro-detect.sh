@@ -3,6 +3,8 @@
 # Detect Linux distribution type
 # v0.1.0
 
+source "$(dirname "${BASH_SOURCE[0]}")/dryrun.sh"
+
 detect_distro() {
     if [[ -f /etc/os-release ]]; then
         local os_id os_name os_id_like
@@ -19,12 +21,24 @@ detect_distro() {
                 DISTRO_TYPE="debian"
                 DISTRO_NAME="$os_name"
                 ;;
+            fedora|rhel|centos|rocky|almalinux|nobara|ultramarine)
+                DISTRO_TYPE="fedora"
+                DISTRO_NAME="$os_name"
+                ;;
+            opensuse|opensuse-tumbleweed|opensuse-leap|opensuse-slowroll|opensuse-microos|sles|sled)
+                DISTRO_TYPE="suse"
+                DISTRO_NAME="$os_name"
+                ;;
             *)
                 # Check ID_LIKE for derivatives (space-tokenized to avoid substring false-positives)
                 if [[ " $os_id_like " == *" arch "* ]]; then
                     DISTRO_TYPE="arch"
                 elif [[ " $os_id_like " == *" debian "* ]] || [[ " $os_id_like " == *" ubuntu "* ]]; then
                     DISTRO_TYPE="debian"
+                elif [[ " $os_id_like " == *" fedora "* ]] || [[ " $os_id_like " == *" rhel "* ]]; then
+                    DISTRO_TYPE="fedora"
+                elif [[ " $os_id_like " == *" suse "* ]] || [[ " $os_id_like " == *" opensuse "* ]]; then
+                    DISTRO_TYPE="suse"
                 else
                     DISTRO_TYPE="unknown"
                 fi
@@ -51,6 +65,16 @@ detect_distro() {
             PKG_INSTALL="sudo apt install -y"
             PKG_UPDATE="sudo apt update"
             ;;
+        fedora)
+            PKG_MGR="dnf"
+            PKG_INSTALL="sudo dnf install -y"
+            PKG_UPDATE="sudo dnf makecache"
+            ;;
+        suse)
+            PKG_MGR="zypper"
+            PKG_INSTALL="sudo zypper --non-interactive install"
+            PKG_UPDATE="sudo zypper --non-interactive refresh"
+            ;;
         *)
             # Fallback: detect by available commands; keep DISTRO_TYPE consistent.
             if command -v pacman &>/dev/null; then
@@ -63,14 +87,30 @@ detect_distro() {
                 PKG_MGR="apt"
                 PKG_INSTALL="sudo apt install -y"
                 PKG_UPDATE="sudo apt update"
+            elif command -v dnf &>/dev/null; then
+                DISTRO_TYPE="fedora"
+                PKG_MGR="dnf"
+                PKG_INSTALL="sudo dnf install -y"
+                PKG_UPDATE="sudo dnf makecache"
+            elif command -v zypper &>/dev/null; then
+                DISTRO_TYPE="suse"
+                PKG_MGR="zypper"
+                PKG_INSTALL="sudo zypper --non-interactive install"
+                PKG_UPDATE="sudo zypper --non-interactive refresh"
             fi
             ;;
     esac
 
+    # Dry run: record packages instead of installing them
+    if is_dry_run && [[ -n "${PKG_INSTALL:-}" ]]; then
+        PKG_INSTALL="_plan_pkg_install"
+        PKG_UPDATE="_plan_pkg_update"
+    fi
+
     export PKG_MGR PKG_INSTALL PKG_UPDATE
 
     if [[ -z "${PKG_INSTALL:-}" ]]; then
-        echo -e "\033[0;31m[✗]\033[0m Unsupported distro '$DISTRO_NAME' — neither pacman nor apt found. Aborting." >&2
+        echo -e "\033[0;31m[✗]\033[0m Unsupported distro '$DISTRO_NAME' — no supported package manager (pacman, apt, dnf, zypper) found. Aborting." >&2
         exit 1
     fi
 }
@@ -0,0 +1,96 @@
+#!/usr/bin/env bash
+# Dry-run contract — preview what an installer would change
+#
+# When MYPCTOOLS_DRY_RUN=1 the shared helpers record planned changes instead
+# of making them. Each change is one "kind<TAB>detail" line appended to
+# $MYPCTOOLS_PLAN_FILE (the TUI's Preview sets this), or printed as
+# "[plan] kind: detail" when running by hand.
+#
+# Kinds: package, link, backup, write, append, mkdir, remove, download, run, shell, setting
+#
+# In dry-run mode sudo and mkdir are replaced with recorders too, so a stray
+# privileged command or directory creation can't touch the system.
+# Bundles opt in with `dry_run = true` in bundle.toml once every side effect
+# in their install.sh goes through these helpers.
+
+[[ -n "$_DRYRUN_SH_LOADED" ]] && return 0
+_DRYRUN_SH_LOADED=1
+
+is_dry_run() { [[ "${MYPCTOOLS_DRY_RUN:-}" == "1" ]]; }
+
+# Usage: plan <kind> <detail>
+# Paths under $HOME are shown as ~/...
+plan() {
+    local kind="$1"
+    local detail="${2//"$HOME"/\~}"
+    if [[ -n "${MYPCTOOLS_PLAN_FILE:-}" ]]; then
+        printf '%s\t%s\n' "$kind" "$detail" >> "$MYPCTOOLS_PLAN_FILE"
+    else
+        echo "[plan] $kind: $detail"
+    fi
+}
+
+# Run a command, or record it as a planned change in dry-run mode.
+# Usage: apply_change <kind> <detail> <command> [args...]
+apply_change() {
+    local kind="$1" detail="$2"
+    shift 2
+    if is_dry_run; then
+        plan "$kind" "$detail"
+        return 0
+    fi
+    "$@"
+}
+
+# Write stdin to a file (replacing it).
+# Usage: write_file <path> << 'EOF' ... EOF
+write_file() {
+    if is_dry_run; then
+        plan write "$1"
+        cat >/dev/null
+        return 0
+    fi
+    cat > "$1"
+}
+
+# Append stdin to a file.
+# Usage: append_file <path> << 'EOF' ... EOF
+append_file() {
+    if is_dry_run; then
+        plan append "$1"
+        cat >/dev/null
+        return 0
+    fi
+    cat >> "$1"
+}
+
+# Package manager stand-ins, swapped into PKG_INSTALL/PKG_UPDATE by distro-detect.sh.
+# Already-installed packages are left out of the plan.
+_plan_pkg_install() {
+    local pkg
+    for pkg in "$@"; do
+        [[ "$pkg" == -* ]] && continue
+        case "${PKG_MGR:-}" in
+            pacman) pacman -Q "$pkg" &>/dev/null && continue ;;
+            apt) dpkg -s "$pkg" &>/dev/null && continue ;;
+            dnf|zypper) rpm -q "$pkg" &>/dev/null && continue ;;
+        esac
+        plan package "$pkg"
+    done
+}
+
+_plan_pkg_update() { plan run "refresh ${PKG_MGR:-package} database"; }
+
+if is_dry_run; then
+    sudo() {
+        [[ "$1" == "-v" ]] && return 0
+        plan run "sudo $*"
+    }
+    mkdir() {
+        local dir
+        for dir in "$@"; do
+            [[ "$dir" == -* || -d "$dir" ]] && continue
+            plan mkdir "$dir"
+        done
+    }
+fi
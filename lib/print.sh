@@ -5,6 +5,8 @@
 [[ -n "$_PRINT_SH_LOADED" ]] && return 0
 _PRINT_SH_LOADED=1
 
+source "$(dirname "${BASH_SOURCE[0]}")/dryrun.sh"
+
 if [[ -t 1 && -z "${NO_COLOR:-}" ]]; then
     RED='\033[0;31m'
     GREEN='\033[0;32m'
@@ -31,6 +33,7 @@ command_exists() { command -v "$1" &>/dev/null; }
 
 # Check network connectivity (GitHub reachable)
 check_network() {
+    is_dry_run && return 0
     if ! curl -fsI https://github.com >/dev/null 2>&1; then
         print_error "No network access to GitHub — check your connection and retry."
         exit 1
@@ -39,6 +42,7 @@ check_network() {
 
 # Prompt for sudo (wrapper)
 ensure_sudo() {
+    is_dry_run && return 0
     sudo -v || { print_error "Sudo access required."; return 1; }
 }
 
@@ -70,6 +74,7 @@ simple_choose() {
 # Prompt for sudo and keep credentials alive in background
 # Call at the start of scripts that need sustained sudo access
 init_sudo() {
+    is_dry_run && return 0
     # Guard against multiple background loops
     if [[ -n "$_INIT_SUDO_PID" ]] && kill -0 "$_INIT_SUDO_PID" 2>/dev/null; then
         return 0
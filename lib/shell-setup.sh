@@ -26,6 +26,11 @@ set_default_shell() {
         return 0
     fi
 
+    if is_dry_run; then
+        plan shell "$shell_path"
+        return 0
+    fi
+
     # Refresh sudo credentials (may have expired during long install)
     print_status "Requesting sudo for shell change..."
     if ! sudo -v; then
@@ -1,10 +1,14 @@
 #!/usr/bin/env bash
 # Safe symlink creation with validation and backup
+# Displaced files are recorded in the backup ledger (lib/backup.sh)
 # Requires: print_status, print_success, print_warning from lib/print.sh
+# Honours MYPCTOOLS_DRY_RUN (see lib/dryrun.sh)
 
 [[ -n "$_SYMLINK_SH_LOADED" ]] && return 0
 _SYMLINK_SH_LOADED=1
 
+source "$(dirname "${BASH_SOURCE[0]}")/backup.sh"
+
 # Usage: safe_symlink <source> <target> [name]
 # Returns 0 on success, 1 on failure
 safe_symlink() {
@@ -36,10 +40,17 @@ safe_symlink() {
         fi
     fi
 
+    if is_dry_run; then
+        [[ -e "$target" || -L "$target" ]] && plan backup "$target"
+        plan link "$target → $resolved_source"
+        return 0
+    fi
+
     # Backup existing file/symlink if it's not ours
     if [[ -e "$target" || -L "$target" ]]; then
         local backup="$target.backup.$(date +%Y%m%d_%H%M%S)"
         mv "$target" "$backup"
+        record_backup "$target" "$backup"
         print_status "Backed up existing $name to: $(basename "$backup")"
     fi
 
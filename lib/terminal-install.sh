@@ -13,9 +13,20 @@ source "$_TERMINAL_INSTALL_DIR/distro-detect.sh"
 
 # Theme selection
 # Reads THEME_FILE var (set by caller) to persist/restore theme across AutoSync runs.
+# MYPCTOOLS_TERMINAL_THEME (terminal.theme in config.toml) skips the prompt.
 select_theme() {
     local theme_file="${THEME_FILE:-}"
 
+    if [ -n "${MYPCTOOLS_TERMINAL_THEME:-}" ]; then
+        THEME="$MYPCTOOLS_TERMINAL_THEME"
+        print_status "Using theme from settings: $THEME"
+        if [ -n "$theme_file" ]; then
+            mkdir -p "$(dirname "$theme_file")"
+            echo "$THEME" > "$theme_file"
+        fi
+        return 0
+    fi
+
     if [ ! -t 0 ]; then
         # Non-interactive (AutoSync): restore persisted theme or use default
         if [ -n "$theme_file" ] && [ -f "$theme_file" ]; then
@@ -76,6 +87,11 @@ install_font() {
         print_warning "pacman install failed, falling back to GitHub download..."
     fi
 
+    if is_dry_run; then
+        plan download "UbuntuMono Nerd Font → $FONT_DIR"
+        return 0
+    fi
+
     mkdir -p "$FONT_DIR"
 
     local api_url="https://api.github.com/repos/ryanoasis/nerd-fonts/releases/latest"
@@ -122,6 +138,11 @@ set_default_terminal() {
     local xdg_terminals="$HOME/.config/xdg-terminals.list"
     local backup_file="$HOME/.config/xdg-terminals.list.${terminal_name}-backup"
 
+    if is_dry_run; then
+        plan setting "default terminal → $terminal_name (asks first)"
+        return 0
+    fi
+
     echo ""
     read -rp "Set $terminal_name as default terminal? [y/N]: " set_default
 
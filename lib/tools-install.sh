@@ -2,11 +2,12 @@
 # mypctools/lib/tools-install.sh
 # Shared tool installation/uninstallation for litebash and litezsh
 # v1.1.0 - Added safe_symlink helper with backup support
+# Honours MYPCTOOLS_DRY_RUN (see lib/dryrun.sh)
 
 # Required variables from caller:
 #   LOCAL_BIN - path to ~/.local/bin
 #   ARCH - uname -m output
-#   PKG_MGR - pacman/apt
+#   PKG_MGR - pacman/apt/dnf/zypper
 #   PKG_INSTALL - install command string
 # Required functions from caller:
 #   print_status, print_success, print_warning, pkg_install
@@ -45,6 +46,11 @@ _tools_install_from_github() {
         return 0
     fi
 
+    if is_dry_run; then
+        plan download "$binary from github.com/$repo → $LOCAL_BIN/$binary"
+        return 0
+    fi
+
     print_status "Installing $binary from GitHub ($repo)..."
 
     local api_url="https://api.github.com/repos/$repo/releases/latest"
@@ -137,6 +143,11 @@ _tools_install_tldr() {
         return 0
     fi
 
+    if is_dry_run; then
+        plan download "tealdeer from github.com/tealdeer-rs/tealdeer → $LOCAL_BIN/tldr"
+        return 0
+    fi
+
     print_status "Installing tldr from GitHub..."
     local api_url="https://api.github.com/repos/tealdeer-rs/tealdeer/releases/latest"
     local auth_header=()
@@ -170,6 +181,11 @@ _tools_install_dysk() {
         return 0
     fi
 
+    if is_dry_run; then
+        plan download "dysk from github.com/Canop/dysk → $LOCAL_BIN/dysk"
+        return 0
+    fi
+
     print_status "Installing dysk from GitHub..."
     local api_url="https://api.github.com/repos/Canop/dysk/releases/latest"
     local download_url
@@ -219,6 +235,11 @@ _tools_install_dust() {
         return 0
     fi
 
+    if is_dry_run; then
+        plan download "dust from github.com/bootandy/dust → $LOCAL_BIN/dust"
+        return 0
+    fi
+
     print_status "Installing dust from GitHub..."
     local api_url="https://api.github.com/repos/bootandy/dust/releases/latest"
     local download_url
@@ -275,6 +296,11 @@ _tools_install_starship() {
         print_success "starship already installed"
         return 0
     fi
+
+    if is_dry_run; then
+        plan download "starship via starship.rs/install.sh → $LOCAL_BIN/starship"
+        return 0
+    fi
     print_status "Installing starship..."
     if curl -fsSL https://starship.rs/install.sh | sh -s -- -y -b "$LOCAL_BIN"; then
         print_success "Installed starship"
@@ -317,8 +343,8 @@ install_all_tools() {
 # Create Debian/Ubuntu symlinks for bat/fd naming differences
 create_debian_symlinks() {
     if [ "$PKG_MGR" = "apt" ]; then
-        [ -f /usr/bin/batcat ] && [ ! -e "$LOCAL_BIN/bat" ] && ln -sf /usr/bin/batcat "$LOCAL_BIN/bat"
-        [ -f /usr/bin/fdfind ] && [ ! -e "$LOCAL_BIN/fd" ] && ln -sf /usr/bin/fdfind "$LOCAL_BIN/fd"
+        [ -f /usr/bin/batcat ] && [ ! -e "$LOCAL_BIN/bat" ] && apply_change link "$LOCAL_BIN/bat → /usr/bin/batcat" ln -sf /usr/bin/batcat "$LOCAL_BIN/bat"
+        [ -f /usr/bin/fdfind ] && [ ! -e "$LOCAL_BIN/fd" ] && apply_change link "$LOCAL_BIN/fd → /usr/bin/fdfind" ln -sf /usr/bin/fdfind "$LOCAL_BIN/fd"
     fi
 }
 
@@ -356,6 +382,12 @@ print_pkg_removal_instructions() {
     elif command -v apt &>/dev/null; then
         print_status "To remove system packages, run:"
         echo "  sudo apt remove eza bat fzf ripgrep fd-find btop micro gh${extra_pkgs:+ $extra_pkgs}"
+    elif command -v dnf &>/dev/null; then
+        print_status "To remove system packages, run:"
+        echo "  sudo dnf remove eza bat fzf ripgrep fd-find btop micro gh${extra_pkgs:+ $extra_pkgs}"
+    elif command -v zypper &>/dev/null; then
+        print_status "To remove system packages, run:"
+        echo "  sudo zypper remove eza bat fzf ripgrep fd btop micro gh${extra_pkgs:+ $extra_pkgs}"
     fi
 }
 
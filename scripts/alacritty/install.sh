@@ -21,6 +21,7 @@ install_alacritty() {
     case "$PKG_MGR" in
         pacman) $PKG_INSTALL alacritty ;;
         apt) $PKG_INSTALL alacritty ;;
+        dnf|zypper) $PKG_INSTALL alacritty ;;
     esac
     print_success "Installed alacritty"
 }
@@ -3,6 +3,7 @@
 # v1.7 - Removed set -e for reliability
 
 SCRIPT_DIR="$(cd "$(dirname "${BASH_SOURCE[0]}")" && pwd)"
+source "$SCRIPT_DIR/../../lib/dryrun.sh"
 
 # Check for jq (required for settings.json manipulation)
 if ! command -v jq &>/dev/null; then
@@ -21,8 +22,10 @@ if [ -d "$SCRIPT_DIR/skills" ]; then
         [ -d "$skill" ] || continue
         skill_name=$(basename "$skill")
         # Remove existing (handles symlinks from older setups)
-        rm -rf ~/.claude/skills/"$skill_name"
-        cp -r "$skill" ~/.claude/skills/
+        if [[ -e ~/.claude/skills/"$skill_name" || -L ~/.claude/skills/"$skill_name" ]]; then
+            apply_change remove "$HOME/.claude/skills/$skill_name" rm -rf ~/.claude/skills/"$skill_name"
+        fi
+        apply_change write "$HOME/.claude/skills/$skill_name/" cp -r "$skill" ~/.claude/skills/
         echo "  Installed skill: $skill_name"
     done
 fi
@@ -30,13 +33,15 @@ fi
 
 # Copy statusline script
 if [ -f "$SCRIPT_DIR/statusline.sh" ]; then
-    cp "$SCRIPT_DIR/statusline.sh" ~/.claude/statusline.sh
-    chmod +x ~/.claude/statusline.sh
+    apply_change write "$HOME/.claude/statusline.sh" cp "$SCRIPT_DIR/statusline.sh" ~/.claude/statusline.sh
+    is_dry_run || chmod +x ~/.claude/statusline.sh
     echo "  Installed statusline.sh"
 
     # Update settings.json with statusLine config
     SETTINGS_FILE=~/.claude/settings.json
-    if [ -f "$SETTINGS_FILE" ]; then
+    if is_dry_run; then
+        plan write "$SETTINGS_FILE (statusLine entry)"
+    elif [ -f "$SETTINGS_FILE" ]; then
         # Merge statusLine into existing settings
         tmp=$(mktemp)
         jq '. + {"statusLine": {"type": "command", "command": "~/.claude/statusline.sh", "padding": 0}}' "$SETTINGS_FILE" > "$tmp" && mv "$tmp" "$SETTINGS_FILE"
@@ -55,9 +60,11 @@ add_alias_if_missing() {
 
     [[ ! -f "$rc_file" ]] && return
     if ! grep -q "$grep_pattern" "$rc_file" 2>/dev/null; then
-        echo "" >> "$rc_file"
-        echo "# Claude Code shortcut" >> "$rc_file"
-        echo "$alias_line" >> "$rc_file"
+        append_file "$rc_file" << EOF
+
+# Claude Code shortcut
+$alias_line
+EOF
         echo "  Added cdsp alias to $rc_file"
     else
         echo "  cdsp alias already exists in $rc_file"
@@ -72,7 +79,7 @@ add_alias_if_missing ~/.zshrc "alias cdsp='claude --dangerously-skip-permissions
 
 # Fish (create config if fish dir exists but config doesn't)
 if [[ -d ~/.config/fish ]]; then
-    [[ ! -f ~/.config/fish/config.fish ]] && touch ~/.config/fish/config.fish
+    [[ ! -f ~/.config/fish/config.fish ]] && apply_change write "$HOME/.config/fish/config.fish" touch ~/.config/fish/config.fish
     add_alias_if_missing ~/.config/fish/config.fish "alias cdsp 'claude --dangerously-skip-permissions'" "alias cdsp "
 fi
 
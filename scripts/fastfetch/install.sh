@@ -20,7 +20,9 @@ install_fastfetch() {
         apt)
             $PKG_INSTALL fastfetch 2>/dev/null || install_fastfetch_deb
             ;;
+        dnf|zypper) $PKG_INSTALL fastfetch ;;
     esac
+    is_dry_run && return 0
 
     if command -v fastfetch &>/dev/null; then
         print_success "Installed fastfetch"
@@ -21,6 +21,7 @@ install_kitty() {
     case "$PKG_MGR" in
         pacman) $PKG_INSTALL kitty ;;
         apt) $PKG_INSTALL kitty ;;
+        dnf|zypper) $PKG_INSTALL kitty ;;
     esac
     print_success "Installed kitty"
 }
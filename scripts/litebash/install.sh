@@ -22,15 +22,21 @@ source "$SCRIPT_DIR/../../lib/distro-detect.sh"
 source "$SCRIPT_DIR/../../lib/shell-setup.sh"
 
 # Install package via package manager
+# Usage: pkg_install <name> <pacman_pkg> <apt_pkg> [dnf_pkg] [zypper_pkg]
+# dnf names default to the apt name, zypper names to the pacman name.
 pkg_install() {
     local name="$1"
     local pacman_pkg="$2"
     local apt_pkg="$3"
+    local dnf_pkg="${4:-$apt_pkg}"
+    local zypper_pkg="${5:-$pacman_pkg}"
 
     local pkg=""
     case "$PKG_MGR" in
         pacman) pkg="$pacman_pkg" ;;
         apt) pkg="$apt_pkg" ;;
+        dnf) pkg="$dnf_pkg" ;;
+        zypper) pkg="$zypper_pkg" ;;
     esac
 
     if [ -n "$pkg" ]; then
@@ -73,7 +79,7 @@ main() {
     pkg_install "fd" "fd" "fd-find"
     pkg_install "btop" "btop" "btop"
     pkg_install "micro" "micro" "micro"
-    pkg_install "gh" "github-cli" "gh"
+    pkg_install "gh" "github-cli" "gh" "gh" "gh"
 
     # Create Debian symlinks + install all GitHub tools
     create_debian_symlinks
@@ -106,7 +112,7 @@ main() {
     install_starship_config
 
     # Verify starship config was created
-    if [[ ! -L "$HOME/.config/starship.toml" ]] && [[ ! -f "$HOME/.config/starship.toml" ]]; then
+    if ! is_dry_run && [[ ! -L "$HOME/.config/starship.toml" ]] && [[ ! -f "$HOME/.config/starship.toml" ]]; then
         print_warning "Starship config not created - creating manually..."
         ln -sf "$(readlink -f "$SCRIPT_DIR/../shared/prompt/starship.toml")" "$HOME/.config/starship.toml"
     fi
@@ -124,10 +130,10 @@ main() {
     if [[ "$needs_clean_bashrc" == "true" ]]; then
         print_warning "Existing .bashrc has conflicting configs (oh-my-bash/bash-it/distro)"
         print_status "Backing up to ~/.bashrc.pre-litebash"
-        cp "$HOME/.bashrc" "$HOME/.bashrc.pre-litebash"
+        apply_change backup "$HOME/.bashrc → ~/.bashrc.pre-litebash" cp "$HOME/.bashrc" "$HOME/.bashrc.pre-litebash"
 
         print_status "Writing clean .bashrc..."
-        cat > "$HOME/.bashrc" << 'BASHRC'
+        write_file "$HOME/.bashrc" << 'BASHRC'
 export PATH="$HOME/.local/bin:$PATH"
 
 # LiteBash
@@ -136,9 +142,11 @@ BASHRC
         print_success "Clean .bashrc created (backup: ~/.bashrc.pre-litebash)"
     elif ! grep -q "litebash/litebash.sh" "$HOME/.bashrc" 2>/dev/null; then
         print_status "Adding LiteBash to ~/.bashrc..."
-        echo '' >> "$HOME/.bashrc"
-        echo '# LiteBash' >> "$HOME/.bashrc"
-        echo '[ -f ~/.local/share/litebash/litebash.sh ] && source ~/.local/share/litebash/litebash.sh' >> "$HOME/.bashrc"
+        append_file "$HOME/.bashrc" << 'BASHRC'
+
+# LiteBash
+[ -f ~/.local/share/litebash/litebash.sh ] && source ~/.local/share/litebash/litebash.sh
+BASHRC
     else
         print_status "LiteBash already in ~/.bashrc"
     fi
@@ -28,6 +28,7 @@ install_zsh() {
 
     print_status "Installing zsh..."
     $PKG_INSTALL zsh || { print_error "Failed to install zsh"; return 1; }
+    is_dry_run && return 0
 
     if command -v zsh &>/dev/null; then
         print_success "Installed zsh"
@@ -49,7 +50,8 @@ install_plugins() {
         print_success "zsh-autosuggestions already installed"
     else
         print_status "Installing zsh-autosuggestions..."
-        if git clone --depth=1 https://github.com/zsh-users/zsh-autosuggestions.git \
+        if apply_change download "zsh-autosuggestions → $plugins_dir/zsh-autosuggestions" \
+            git clone --depth=1 https://github.com/zsh-users/zsh-autosuggestions.git \
             "$plugins_dir/zsh-autosuggestions" 2>/dev/null; then
             print_success "Installed zsh-autosuggestions"
         else
@@ -62,7 +64,8 @@ install_plugins() {
         print_success "zsh-syntax-highlighting already installed"
     else
         print_status "Installing zsh-syntax-highlighting..."
-        if git clone --depth=1 https://github.com/zsh-users/zsh-syntax-highlighting.git \
+        if apply_change download "zsh-syntax-highlighting → $plugins_dir/zsh-syntax-highlighting" \
+            git clone --depth=1 https://github.com/zsh-users/zsh-syntax-highlighting.git \
             "$plugins_dir/zsh-syntax-highlighting" 2>/dev/null; then
             print_success "Installed zsh-syntax-highlighting"
         else
@@ -72,15 +75,21 @@ install_plugins() {
 }
 
 # Install package via package manager
+# Usage: pkg_install <name> <pacman_pkg> <apt_pkg> [dnf_pkg] [zypper_pkg]
+# dnf names default to the apt name, zypper names to the pacman name.
 pkg_install() {
     local name="$1"
     local pacman_pkg="$2"
     local apt_pkg="$3"
+    local dnf_pkg="${4:-$apt_pkg}"
+    local zypper_pkg="${5:-$pacman_pkg}"
 
     local pkg=""
     case "$PKG_MGR" in
         pacman) pkg="$pacman_pkg" ;;
         apt) pkg="$apt_pkg" ;;
+        dnf) pkg="$dnf_pkg" ;;
+        zypper) pkg="$zypper_pkg" ;;
     esac
 
     if [ -n "$pkg" ]; then
@@ -130,7 +139,7 @@ main() {
     pkg_install "fd" "fd" "fd-find"
     pkg_install "btop" "btop" "btop"
     pkg_install "micro" "micro" "micro"
-    pkg_install "gh" "github-cli" "gh"
+    pkg_install "gh" "github-cli" "gh" "gh" "gh"
 
     # Create Debian symlinks + install all GitHub tools
     create_debian_symlinks
@@ -164,7 +173,7 @@ main() {
     install_starship_config
 
     # Verify starship config was created
-    if [[ ! -L "$HOME/.config/starship.toml" ]] && [[ ! -f "$HOME/.config/starship.toml" ]]; then
+    if ! is_dry_run && [[ ! -L "$HOME/.config/starship.toml" ]] && [[ ! -f "$HOME/.config/starship.toml" ]]; then
         print_warning "Starship config not created - creating manually..."
         ln -sf "$(readlink -f "$SCRIPT_DIR/../shared/prompt/starship.toml")" "$HOME/.config/starship.toml"
     fi
@@ -179,7 +188,7 @@ main() {
         fi
     fi
 
-    if [[ "$needs_clean_zshrc" == "true" ]]; then
+    if [[ "$needs_clean_zshrc" == "true" ]] && ! is_dry_run; then
         print_warning "Existing .zshrc has conflicting configs (oh-my-zsh/p10k/distro)"
         print_warning "LiteZsh needs a clean .zshrc — your current one will be backed up to ~/.zshrc.pre-litezsh"
         read -rp "Overwrite ~/.zshrc? [y/N] " _confirm
@@ -192,11 +201,11 @@ main() {
 
     if [[ "$needs_clean_zshrc" == "true" ]]; then
         print_status "Backing up to ~/.zshrc.pre-litezsh"
-        cp "$HOME/.zshrc" "$HOME/.zshrc.pre-litezsh"
+        apply_change backup "$HOME/.zshrc → ~/.zshrc.pre-litezsh" cp "$HOME/.zshrc" "$HOME/.zshrc.pre-litezsh"
 
         # Preserve any user PATH/alias lines that aren't part of the conflicting framework
         print_status "Writing clean .zshrc..."
-        cat > "$HOME/.zshrc" << 'ZSHRC'
+        write_file "$HOME/.zshrc" << 'ZSHRC'
 export PATH="$HOME/.local/bin:$PATH"
 
 # LiteZsh
@@ -205,16 +214,18 @@ ZSHRC
         print_success "Clean .zshrc created (backup: ~/.zshrc.pre-litezsh)"
     elif ! grep -q "litezsh/litezsh.zsh" "$HOME/.zshrc" 2>/dev/null; then
         print_status "Adding LiteZsh to ~/.zshrc..."
-        touch "$HOME/.zshrc"
-        echo '' >> "$HOME/.zshrc"
-        echo '# LiteZsh' >> "$HOME/.zshrc"
-        echo '[[ -f ~/.local/share/litezsh/litezsh.zsh ]] && source ~/.local/share/litezsh/litezsh.zsh' >> "$HOME/.zshrc"
+        append_file "$HOME/.zshrc" << 'ZSHRC'
+
+# LiteZsh
+[[ -f ~/.local/share/litezsh/litezsh.zsh ]] && source ~/.local/share/litezsh/litezsh.zsh
+ZSHRC
     else
         print_status "LiteZsh already in ~/.zshrc"
     fi
 
     # Set zsh as default shell (after .zshrc is set up)
-    set_default_shell "$(command -v zsh)"
+    # zsh is only planned (not installed) during a dry run
+    set_default_shell "$(command -v zsh || echo /usr/bin/zsh)"
 
     echo ""
     print_success "Installation complete!"
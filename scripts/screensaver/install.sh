@@ -7,6 +7,7 @@ SCRIPT_DIR="$(cd "$(dirname "${BASH_SOURCE[0]}")" && pwd)"
 MYPCTOOLS_ROOT="$(cd "$SCRIPT_DIR/../.." && pwd)"
 
 source "$MYPCTOOLS_ROOT/lib/print.sh"
+source "$MYPCTOOLS_ROOT/lib/backup.sh"
 source "$MYPCTOOLS_ROOT/lib/distro-detect.sh"
 
 ASSETS_DIR="$HOME/.local/share/mypctools-screensaver"
@@ -51,6 +52,8 @@ if ! command_exists pipx; then
     case "$DISTRO_TYPE" in
         arch)   sudo pacman -S --noconfirm --needed python-pipx ;;
         debian) sudo apt install -y pipx ;;
+        fedora) sudo dnf install -y pipx ;;
+        suse)   sudo zypper --non-interactive install python3-pipx ;;
         *)
             print_error "Cannot install pipx on this distro. Install it manually."
             exit 1
@@ -83,6 +86,7 @@ if ! command_exists hypridle; then
     case "$DISTRO_TYPE" in
         arch)   sudo pacman -S --noconfirm --needed hypridle ;;
         debian) sudo apt install -y hypridle ;;
+        suse)   sudo zypper --non-interactive install hypridle ;;
         *)      print_warning "Cannot auto-install hypridle. Install it manually." ;;
     esac
     if command_exists hypridle; then
@@ -126,7 +130,8 @@ if [[ -f "$HYPR_CONF" ]]; then
         print_success "Hyprland window rules already configured"
     else
         print_info "Adding Hyprland window rules..."
-        cp "$HYPR_CONF" "$HYPR_CONF.backup.$(date +%Y%m%d_%H%M%S)"
+        backup="$HYPR_CONF.backup.$(date +%Y%m%d_%H%M%S)"
+        cp "$HYPR_CONF" "$backup" && record_backup "$HYPR_CONF" "$backup"
         print_info "Backed up hyprland.conf"
 
         cat >> "$HYPR_CONF" << 'HYPR_EOF'
@@ -155,7 +160,8 @@ if [[ -f "$HYPRIDLE_CONF" ]]; then
     if grep -q "$MARKER_START" "$HYPRIDLE_CONF"; then
         print_success "hypridle screensaver listener already configured"
     else
-        cp "$HYPRIDLE_CONF" "$HYPRIDLE_CONF.backup.$(date +%Y%m%d_%H%M%S)"
+        backup="$HYPRIDLE_CONF.backup.$(date +%Y%m%d_%H%M%S)"
+        cp "$HYPRIDLE_CONF" "$backup" && record_backup "$HYPRIDLE_CONF" "$backup"
         print_info "Backed up existing hypridle.conf"
 
         cat >> "$HYPRIDLE_CONF" << IDLE_EOF
@@ -65,6 +65,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, tea.Quit
 
 	case ToastMsg:
+		toastDuration := m.shared.Config.UI.ToastDuration()
 		m.toast = msg.Text
 		m.toastError = msg.IsError
 		m.toastFading = false
@@ -97,6 +98,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "ctrl+c":
+			if len(m.stack) > 0 {
+				if s, ok := m.stack[len(m.stack)-1].(Interrupter); ok && s.HandlesInterrupt() {
+					break
+				}
+			}
 			return m, tea.Quit
 		case "esc":
 			// Let the active screen handle esc when it has an internal state
@@ -1,10 +1,6 @@
 package app
 
-import (
-	"time"
-
-	tea "github.com/charmbracelet/bubbletea"
-)
+import tea "github.com/charmbracelet/bubbletea"
 
 // Screen is implemented by every TUI screen.
 type Screen interface {
@@ -19,6 +15,13 @@ type Screen interface {
 	HandlesBack() bool
 }
 
+// Interrupter is implemented by screens that run a command in the foreground.
+// While HandlesInterrupt returns true the root forwards ctrl+c to the screen,
+// which passes it on to the command, instead of quitting.
+type Interrupter interface {
+	HandlesInterrupt() bool
+}
+
 // NavigateMsg pushes a new screen onto the stack.
 type NavigateMsg struct {
 	Screen Screen
@@ -59,6 +62,3 @@ func PopScreen() tea.Cmd {
 func Toast(text string, isError bool) tea.Cmd {
 	return func() tea.Msg { return ToastMsg{Text: text, IsError: isError} }
 }
-
-// toastDuration is how long a toast stays visible.
-const toastDuration = 3 * time.Second
@@ -0,0 +1,196 @@
+// Package archnews reads the Arch Linux news feed so manual-intervention
+// notices are seen before a system upgrade.
+package archnews
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/reisset/mypctools/tui/internal/logging"
+)
+
+// FeedURL is the official news feed.
+const FeedURL = "https://archlinux.org/feeds/news/"
+
+// FileEnv names a feed file to read instead of fetching (offline use, testing).
+const FileEnv = "MYPCTOOLS_ARCH_NEWS"
+
+// cacheName is the last fetched feed under logging.DataDir.
+const cacheName = "arch-news.xml"
+
+// pacmanLog is where pacman records upgrades, used when mypctools has not
+// recorded one yet.
+var pacmanLog = "/var/log/pacman.log"
+
+var httpClient = &http.Client{Timeout: 10 * time.Second}
+
+// Item is one news post.
+type Item struct {
+	Title     string
+	Link      string
+	Published time.Time
+	Body      string // Plain text, paragraphs separated by blank lines
+}
+
+type rss struct {
+	Items []struct {
+		Title       string `xml:"title"`
+		Link        string `xml:"link"`
+		PubDate     string `xml:"pubDate"`
+		Description string `xml:"description"`
+	} `xml:"channel>item"`
+}
+
+// Load returns the news feed, newest first. It reads $MYPCTOOLS_ARCH_NEWS when
+// set; otherwise it fetches the feed, caching it, and falls back to the cache
+// when offline.
+func Load() ([]Item, error) {
+	if path := os.Getenv(FileEnv); path != "" {
+		return parseFile(path)
+	}
+	cache := ""
+	if dir := logging.DataDir(); dir != "" {
+		cache = filepath.Join(dir, cacheName)
+	}
+	data, fetchErr := fetch()
+	if fetchErr == nil {
+		if cache != "" {
+			tmp := cache + ".tmp"
+			if os.WriteFile(tmp, data, 0644) == nil {
+				os.Rename(tmp, cache)
+			}
+		}
+		return Parse(strings.NewReader(string(data)))
+	}
+	if cache == "" {
+		return nil, fetchErr
+	}
+	items, err := parseFile(cache)
+	if os.IsNotExist(err) {
+		return nil, fetchErr
+	}
+	return items, err
+}
+
+func fetch() ([]byte, error) {
+	resp, err := httpClient.Get(FeedURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch Arch news: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch Arch news: HTTP %d", resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+func parseFile(path string) ([]Item, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Parse(f)
+}
+
+// Parse reads an RSS 2.0 feed. Items without a parsable date are dropped,
+// since they cannot be compared with the last update.
+func Parse(r io.Reader) ([]Item, error) {
+	var feed rss
+	if err := xml.NewDecoder(r).Decode(&feed); err != nil {
+		return nil, fmt.Errorf("invalid news feed: %w", err)
+	}
+	var items []Item
+	for _, it := range feed.Items {
+		t, err := parseDate(it.PubDate)
+		if err != nil {
+			continue
+		}
+		items = append(items, Item{
+			Title:     strings.TrimSpace(it.Title),
+			Link:      strings.TrimSpace(it.Link),
+			Published: t,
+			Body:      plainText(it.Description),
+		})
+	}
+	sort.SliceStable(items, func(i, j int) bool { return items[i].Published.After(items[j].Published) })
+	return items, nil
+}
+
+func parseDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range []string{time.RFC1123Z, time.RFC1123, time.RFC3339} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unknown date format: %q", s)
+}
+
+var (
+	tagGaps   = regexp.MustCompile(`>[ \t]*\n\s*<`)
+	paraTags  = regexp.MustCompile(`(?i)<\s*(/p|/pre|/ul|/ol|/h[1-6])\s*>`)
+	breakTags = regexp.MustCompile(`(?i)<\s*(br|/li)\s*/?>`)
+	listTags  = regexp.MustCompile(`(?i)<\s*li[^>]*>`)
+	anyTag    = regexp.MustCompile(`<[^>]*>`)
+	blankRuns = regexp.MustCompile(`\n{3,}`)
+)
+
+// plainText turns the feed's HTML description into readable text.
+func plainText(s string) string {
+	s = tagGaps.ReplaceAllString(s, "><") // Source newlines between tags
+	s = paraTags.ReplaceAllString(s, "\n\n")
+	s = breakTags.ReplaceAllString(s, "\n")
+	s = listTags.ReplaceAllString(s, "• ")
+	s = html.UnescapeString(anyTag.ReplaceAllString(s, ""))
+	lines := strings.Split(s, "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimSpace(l)
+	}
+	return strings.TrimSpace(blankRuns.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+}
+
+// Since returns the items published after t.
+func Since(items []Item, t time.Time) []Item {
+	var out []Item
+	for _, it := range items {
+		if it.Published.After(t) {
+			out = append(out, it)
+		}
+	}
+	return out
+}
+
+// LastPacmanUpgrade returns when pacman last started a full system upgrade,
+// or the zero time if the log has none.
+func LastPacmanUpgrade() time.Time {
+	f, err := os.Open(pacmanLog)
+	if err != nil {
+		return time.Time{}
+	}
+	defer f.Close()
+
+	// "[2024-03-01T10:00:00+0100] [PACMAN] starting full system upgrade"
+	var last time.Time
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		stamp, msg, ok := strings.Cut(strings.TrimPrefix(sc.Text(), "["), "]")
+		if !ok || msg != " [PACMAN] starting full system upgrade" {
+			continue
+		}
+		if t, err := time.Parse("2006-01-02T15:04:05-0700", stamp); err == nil {
+			last = t
+		}
+	}
+	return last
+}
@@ -0,0 +1,109 @@
+package archnews
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func loadFixture(t *testing.T) []Item {
+	t.Helper()
+	items, err := parseFile(filepath.Join("testdata", "news.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return items
+}
+
+func TestParse(t *testing.T) {
+	items := loadFixture(t)
+	want := []struct {
+		title     string
+		published string
+	}{
+		{"Manual intervention required", "2024-03-12T18:30:00Z"},
+		{"Middle news", "2024-02-10T08:00:00Z"},
+		{"Older news", "2024-01-15T12:00:00Z"},
+	}
+	if len(items) != len(want) {
+		t.Fatalf("got %d items, want %d (undated item dropped)", len(items), len(want))
+	}
+	for i, w := range want {
+		if items[i].Title != w.title {
+			t.Errorf("item %d title = %q, want %q", i, items[i].Title, w.title)
+		}
+		if got := items[i].Published.UTC().Format(time.RFC3339); got != w.published {
+			t.Errorf("item %d published = %s, want %s", i, got, w.published)
+		}
+	}
+	if got, want := items[0].Link, "https://archlinux.org/news/manual-intervention-required/"; got != want {
+		t.Errorf("link = %q, want %q", got, want)
+	}
+}
+
+func TestPlainText(t *testing.T) {
+	items := loadFixture(t)
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{"Manual intervention required", "The foo package & its plugins moved.\n\n" +
+			"Before upgrading:\n\n" +
+			"• remove foo-git\n" +
+			"• run pacman -Syu\n\n" +
+			"Quotes: \"bar\" > 'baz'"},
+		{"Middle news", "First line\nsecond line"},
+		{"Older news", "Nothing to do for foo bar."},
+	}
+	for _, tt := range tests {
+		for _, it := range items {
+			if it.Title == tt.title && it.Body != tt.want {
+				t.Errorf("%s body = %q, want %q", tt.title, it.Body, tt.want)
+			}
+		}
+	}
+}
+
+func TestSince(t *testing.T) {
+	items := loadFixture(t)
+	middle := items[1].Published
+	tests := []struct {
+		name  string
+		after time.Time
+		want  int
+	}{
+		{"zero time", time.Time{}, 3},
+		{"just before middle", middle.Add(-time.Second), 2},
+		{"exactly middle", middle, 1},
+		{"after newest", items[0].Published, 0},
+	}
+	for _, tt := range tests {
+		if got := len(Since(items, tt.after)); got != tt.want {
+			t.Errorf("%s: Since = %d items, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLastPacmanUpgrade(t *testing.T) {
+	defer func(orig string) { pacmanLog = orig }(pacmanLog)
+
+	pacmanLog = filepath.Join("testdata", "pacman.log")
+	want := time.Date(2024, 3, 5, 19, 15, 43, 0, time.UTC)
+	if got := LastPacmanUpgrade(); !got.Equal(want) {
+		t.Errorf("LastPacmanUpgrade() = %v, want %v", got, want)
+	}
+
+	empty := filepath.Join(t.TempDir(), "pacman.log")
+	if err := os.WriteFile(empty, []byte("[2024-03-01T10:00:00+0100] [PACMAN] Running 'pacman -S vim'\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	pacmanLog = empty
+	if got := LastPacmanUpgrade(); !got.IsZero() {
+		t.Errorf("log without upgrades: LastPacmanUpgrade() = %v, want zero", got)
+	}
+	pacmanLog = filepath.Join(t.TempDir(), "missing.log")
+	if got := LastPacmanUpgrade(); !got.IsZero() {
+		t.Errorf("missing log: LastPacmanUpgrade() = %v, want zero", got)
+	}
+}
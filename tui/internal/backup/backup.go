@@ -0,0 +1,260 @@
+// Package backup tracks files that install scripts moved or copied aside
+// (see lib/backup.sh) so they can be compared, restored, or pruned.
+package backup
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/reisset/mypctools/tui/internal/bundle"
+	"github.com/reisset/mypctools/tui/internal/logging"
+)
+
+// ledgerName is the ledger file under logging.DataDir, appended to by lib/backup.sh.
+const ledgerName = "backups.jsonl"
+
+// Entry is one backed-up file.
+type Entry struct {
+	Bundle   string    `json:"bundle"`
+	Original string    `json:"original"` // Path the file was displaced from
+	Backup   string    `json:"backup"`   // Where it lives now
+	Time     time.Time `json:"time"`
+}
+
+// ledgerMu serialises rewrites of the ledger within this process.
+var ledgerMu sync.Mutex
+
+func ledgerPath() (string, error) {
+	dir := logging.DataDir()
+	if dir == "" {
+		return "", fmt.Errorf("failed to determine data directory")
+	}
+	return filepath.Join(dir, ledgerName), nil
+}
+
+// List returns every backup that still exists on disk, newest first. Backups
+// made before the ledger existed are picked up by looking for
+// "<link>.backup.*" next to each bundle's manifest links.
+func List() ([]Entry, error) {
+	ledgerMu.Lock()
+	defer ledgerMu.Unlock()
+
+	entries, err := readLedger()
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(entries))
+	var out []Entry
+	for _, e := range entries {
+		if seen[e.Backup] || !exists(e.Backup) {
+			continue
+		}
+		seen[e.Backup] = true
+		out = append(out, e)
+	}
+	for _, e := range scanLinks() {
+		if !seen[e.Backup] {
+			seen[e.Backup] = true
+			out = append(out, e)
+		}
+	}
+
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Time.After(out[j].Time) })
+	return out, nil
+}
+
+// ForBundle returns the existing backups recorded for one bundle.
+func ForBundle(id string) ([]Entry, error) {
+	all, err := List()
+	if err != nil {
+		return nil, err
+	}
+	var out []Entry
+	for _, e := range all {
+		if e.Bundle == id {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+// Restore puts a backup back at its original path. Whatever is there now is
+// removed if it is a symlink, or itself backed up (and recorded) if it is a
+// real file, so a restore never loses data.
+func Restore(e Entry) error {
+	if !exists(e.Backup) {
+		return fmt.Errorf("backup no longer exists: %s", e.Backup)
+	}
+
+	ledgerMu.Lock()
+	defer ledgerMu.Unlock()
+
+	var displaced *Entry
+	if info, err := os.Lstat(e.Original); err == nil {
+		if info.Mode()&os.ModeSymlink != 0 {
+			if err := os.Remove(e.Original); err != nil {
+				return err
+			}
+		} else {
+			now := time.Now()
+			aside := e.Original + ".backup." + now.Format("20060102_150405")
+			for i := 1; exists(aside); i++ {
+				aside = fmt.Sprintf("%s.backup.%s.%d", e.Original, now.Format("20060102_150405"), i)
+			}
+			if err := os.Rename(e.Original, aside); err != nil {
+				return err
+			}
+			displaced = &Entry{Bundle: e.Bundle, Original: e.Original, Backup: aside, Time: now}
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(e.Original), 0755); err != nil {
+		return err
+	}
+	if err := os.Rename(e.Backup, e.Original); err != nil {
+		return err
+	}
+	return rewriteLedger(e.Backup, displaced)
+}
+
+// Prune deletes a backup and drops it from the ledger.
+func Prune(e Entry) error {
+	ledgerMu.Lock()
+	defer ledgerMu.Unlock()
+
+	if err := os.RemoveAll(e.Backup); err != nil {
+		return err
+	}
+	return rewriteLedger(e.Backup, nil)
+}
+
+// readLedger parses the ledger, skipping malformed lines. A missing ledger
+// is not an error.
+func readLedger() ([]Entry, error) {
+	path, err := ledgerPath()
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []Entry
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		var e Entry
+		if json.Unmarshal(sc.Bytes(), &e) != nil || e.Original == "" || e.Backup == "" {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	return entries, sc.Err()
+}
+
+// rewriteLedger drops the entry for removed and entries whose backup is gone,
+// optionally appends add, and atomically replaces the ledger.
+func rewriteLedger(removed string, add *Entry) error {
+	entries, err := readLedger()
+	if err != nil {
+		return err
+	}
+	path, err := ledgerPath()
+	if err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	write := func(e Entry) error {
+		line, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		b.Write(line)
+		b.WriteByte('\n')
+		return nil
+	}
+	for _, e := range entries {
+		if e.Backup == removed || !exists(e.Backup) {
+			continue
+		}
+		if err := write(e); err != nil {
+			return err
+		}
+	}
+	if add != nil {
+		if err := write(*add); err != nil {
+			return err
+		}
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(b.String()), 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// scanLinks finds untracked "<link>.backup.<timestamp>" files left by
+// safe_symlink next to each bundle's links.
+func scanLinks() []Entry {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil
+	}
+	var out []Entry
+	for _, b := range bundle.All() {
+		for _, link := range b.Links {
+			original := filepath.Join(home, link)
+			matches, _ := filepath.Glob(original + ".backup.*")
+			for _, m := range matches {
+				e := Entry{Bundle: b.ID, Original: original, Backup: m}
+				if t, err := time.ParseInLocation("20060102_150405",
+					strings.TrimPrefix(m, original+".backup."), time.Local); err == nil {
+					e.Time = t
+				} else if info, err := os.Lstat(m); err == nil {
+					e.Time = info.ModTime()
+				}
+				out = append(out, e)
+			}
+		}
+	}
+	return out
+}
+
+func exists(path string) bool {
+	_, err := os.Lstat(path)
+	return err == nil
+}
+
+// Diff returns a unified diff from the backup to what is at the original
+// path now ("" when they are identical). Directories are compared recursively.
+func Diff(e Entry) (string, error) {
+	if !exists(e.Original) {
+		return "", fmt.Errorf("%s no longer exists", e.Original)
+	}
+	cmd := exec.Command("diff", "-ru", "--label", "backup", "--label", "current", e.Backup, e.Original)
+	out, err := cmd.Output()
+	if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+		return string(out), nil // Exit 1 just means the files differ
+	}
+	if err != nil {
+		return "", fmt.Errorf("diff failed: %w", err)
+	}
+	return string(out), nil
+}
@@ -1,17 +1,172 @@
 package bundle
 
 import (
+	"crypto/sha256"
+	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 )
 
-// IsInstalled checks if a bundle is installed by testing its marker path.
+// State is the detected installation state of a bundle.
+type State int
+
+const (
+	StateMissing   State = iota // No tracked path exists
+	StatePartial                // Some tracked paths exist, others are missing
+	StateDrifted                // Everything exists but a link or copy no longer matches the repo
+	StateInstalled              // Everything exists and matches
+)
+
+func (s State) String() string {
+	switch s {
+	case StatePartial:
+		return "partial"
+	case StateDrifted:
+		return "drifted"
+	case StateInstalled:
+		return "installed"
+	default:
+		return "missing"
+	}
+}
+
+// Status is the result of Detect.
+type Status struct {
+	State    State
+	Problems []string // Human-readable reasons for partial/drifted (paths shown as ~/...)
+}
+
+// IsInstalled reports whether any part of the bundle is present.
+// Partial and drifted installs count as installed so they can be reinstalled or removed.
 func IsInstalled(b *Bundle) bool {
+	return Detect(b).State != StateMissing
+}
+
+// Detect checks every marker, link and copy declared in the bundle manifest.
+// Links must resolve into the bundle's own directory (or scripts/shared);
+// copies must hash-match their source in the repo.
+func Detect(b *Bundle) Status {
 	home, err := os.UserHomeDir()
+	if err != nil || b.TrackedCount() == 0 {
+		return Status{State: StateMissing}
+	}
+
+	var missing, drifted []string
+	present := 0
+
+	for _, mk := range b.Markers {
+		if _, err := os.Stat(filepath.Join(home, mk)); err != nil {
+			missing = append(missing, "missing ~/"+mk)
+			continue
+		}
+		present++
+	}
+
+	for _, ln := range b.Links {
+		path := filepath.Join(home, ln)
+		fi, err := os.Lstat(path)
+		if err != nil {
+			missing = append(missing, "missing ~/"+ln)
+			continue
+		}
+		present++
+		if fi.Mode()&os.ModeSymlink == 0 {
+			drifted = append(drifted, fmt.Sprintf("~/%s is not a symlink", ln))
+			continue
+		}
+		target, err := filepath.EvalSymlinks(path)
+		if err != nil {
+			drifted = append(drifted, fmt.Sprintf("~/%s is a broken symlink", ln))
+			continue
+		}
+		if !b.ownsPath(target) {
+			drifted = append(drifted, fmt.Sprintf("~/%s → %s", ln, target))
+		}
+	}
+
+	dsts := make([]string, 0, len(b.Copies))
+	for dst := range b.Copies {
+		dsts = append(dsts, dst)
+	}
+	sort.Strings(dsts)
+	for _, dst := range dsts {
+		src := b.Copies[dst]
+		path := filepath.Join(home, dst)
+		if _, err := os.Stat(path); err != nil {
+			missing = append(missing, "missing ~/"+dst)
+			continue
+		}
+		present++
+		if b.Dir == "" {
+			continue
+		}
+		same, err := sameContent(path, filepath.Join(b.Dir, src))
+		if err != nil || !same {
+			drifted = append(drifted, fmt.Sprintf("~/%s differs from repo copy", dst))
+		}
+	}
+
+	switch {
+	case present == 0:
+		return Status{State: StateMissing}
+	case len(missing) > 0:
+		return Status{State: StatePartial, Problems: append(missing, drifted...)}
+	case len(drifted) > 0:
+		return Status{State: StateDrifted, Problems: drifted}
+	default:
+		return Status{State: StateInstalled}
+	}
+}
+
+// TrackedCount returns the number of paths Detect checks.
+func (b *Bundle) TrackedCount() int {
+	return len(b.Markers) + len(b.Links) + len(b.Copies)
+}
+
+// ownsPath reports whether an absolute path lives under the bundle directory
+// or the shared scripts directory.
+func (b *Bundle) ownsPath(path string) bool {
+	if b.Dir == "" {
+		return true
+	}
+	dir, err := filepath.EvalSymlinks(b.Dir)
 	if err != nil {
-		return false
+		dir = b.Dir
+	}
+	shared := filepath.Join(filepath.Dir(dir), "shared")
+	return isWithin(path, dir) || isWithin(path, shared)
+}
+
+func isWithin(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// sameContent compares two files by SHA-256.
+func sameContent(a, b string) (bool, error) {
+	ha, err := fileHash(a)
+	if err != nil {
+		return false, err
+	}
+	hb, err := fileHash(b)
+	if err != nil {
+		return false, err
+	}
+	return ha == hb, nil
+}
+
+func fileHash(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
 	}
-	markerPath := filepath.Join(home, b.MarkerPath)
-	_, err = os.Stat(markerPath)
-	return err == nil
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
 }
@@ -0,0 +1,147 @@
+package bundle
+
+import (
+	"fmt"
+	"strings"
+)
+
+// InstallOrder resolves the given bundle IDs plus their transitive Requires
+// into a dependency-first install order. Independent bundles keep display order.
+func InstallOrder(ids []string) ([]Bundle, error) {
+	return installOrder(All(), ids)
+}
+
+func installOrder(all []Bundle, ids []string) ([]Bundle, error) {
+	byID := make(map[string]Bundle, len(all))
+	for _, b := range all {
+		byID[b.ID] = b
+	}
+
+	// Collect the closure of requested bundles and their dependencies.
+	need := make(map[string]bool)
+	var visit func(id string) error
+	visit = func(id string) error {
+		if need[id] {
+			return nil
+		}
+		b, ok := byID[id]
+		if !ok {
+			return fmt.Errorf("unknown bundle: %s", id)
+		}
+		need[id] = true
+		for _, dep := range b.Requires {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for _, id := range ids {
+		if err := visit(id); err != nil {
+			return nil, err
+		}
+	}
+
+	// Kahn's algorithm, scanning in display order so ties stay stable.
+	placed := make(map[string]bool, len(need))
+	order := make([]Bundle, 0, len(need))
+	for len(order) < len(need) {
+		progressed := false
+		for _, b := range all {
+			if !need[b.ID] || placed[b.ID] {
+				continue
+			}
+			ready := true
+			for _, dep := range b.Requires {
+				if !placed[dep] {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				placed[b.ID] = true
+				order = append(order, b)
+				progressed = true
+			}
+		}
+		if !progressed {
+			var stuck []string
+			for id := range need {
+				if !placed[id] {
+					stuck = append(stuck, id)
+				}
+			}
+			return nil, fmt.Errorf("dependency cycle between: %s", strings.Join(stuck, ", "))
+		}
+	}
+	return order, nil
+}
+
+// MissingRequirements returns the bundles that must be installed before b,
+// in install order, skipping any that are already installed.
+func MissingRequirements(b Bundle) ([]Bundle, error) {
+	order, err := InstallOrder([]string{b.ID})
+	if err != nil {
+		return nil, err
+	}
+	var missing []Bundle
+	for _, dep := range order {
+		if dep.ID != b.ID && !IsInstalled(&dep) {
+			missing = append(missing, dep)
+		}
+	}
+	return missing, nil
+}
+
+// Dependents returns the installed bundles that directly require id.
+func Dependents(id string) []Bundle {
+	var out []Bundle
+	for _, b := range All() {
+		for _, dep := range b.Requires {
+			if dep == id && IsInstalled(&b) {
+				out = append(out, b)
+				break
+			}
+		}
+	}
+	return out
+}
+
+// InstalledConflicts returns the installed bundles that conflict with b.
+// Conflicts are symmetric: declaring one side in either manifest is enough.
+func InstalledConflicts(b Bundle) []Bundle {
+	var out []Bundle
+	for _, other := range All() {
+		if other.ID == b.ID || !ConflictsWith(b, other) {
+			continue
+		}
+		if IsInstalled(&other) {
+			out = append(out, other)
+		}
+	}
+	return out
+}
+
+// ConflictsWith reports whether either bundle declares a conflict with the other.
+func ConflictsWith(a, b Bundle) bool {
+	for _, id := range a.Conflicts {
+		if id == b.ID {
+			return true
+		}
+	}
+	for _, id := range b.Conflicts {
+		if id == a.ID {
+			return true
+		}
+	}
+	return false
+}
+
+// Names joins bundle display names with ", ".
+func Names(bundles []Bundle) string {
+	names := make([]string, len(bundles))
+	for i, b := range bundles {
+		names[i] = b.Name
+	}
+	return strings.Join(names, ", ")
+}
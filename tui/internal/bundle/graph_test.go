@@ -0,0 +1,163 @@
+package bundle
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// loadGraph registers a diamond (app requires lib and ui, which both require
+// base), a bundle with no relations and a conflicting pair. Each bundle
+// counts as installed once $HOME/.<id> exists. app sorts first for display
+// so the order has to come from the requirements.
+func loadGraph(t *testing.T) (home string) {
+	t.Helper()
+	root := t.TempDir()
+	home = t.TempDir()
+	t.Setenv("HOME", home)
+	manifests := map[string]string{
+		"app":     "order = 5\nrequires = [\"lib\", \"ui\"]\n",
+		"base":    "order = 10\n",
+		"lib":     "order = 20\nrequires = [\"base\"]\n",
+		"ui":      "order = 30\nrequires = [\"base\"]\n",
+		"solo":    "order = 40\n",
+		"x11":     "order = 50\nconflicts = [\"wayland\"]\n",
+		"wayland": "order = 51\n",
+	}
+	for id, extra := range manifests {
+		writeBundle(t, root, id, "name = \""+id+"\"\ndescription = \"d\"\nmarkers = [\"."+id+"\"]\n"+extra)
+	}
+	if err := Load(root); err != nil {
+		t.Fatal(err)
+	}
+	if broken := Broken(); len(broken) > 0 {
+		t.Fatalf("invalid test manifests: %v", broken)
+	}
+	return home
+}
+
+// install marks bundles as installed.
+func install(t *testing.T, home string, ids ...string) {
+	t.Helper()
+	for _, id := range ids {
+		if err := os.WriteFile(filepath.Join(home, "."+id), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func ids(bundles []Bundle) []string {
+	out := []string{}
+	for _, b := range bundles {
+		out = append(out, b.ID)
+	}
+	return out
+}
+
+func find(t *testing.T, id string) Bundle {
+	t.Helper()
+	b, ok := Find(id)
+	if !ok {
+		t.Fatalf("bundle %s not loaded", id)
+	}
+	return b
+}
+
+func TestInstallOrder(t *testing.T) {
+	loadGraph(t)
+	tests := []struct {
+		ids  []string
+		want []string
+	}{
+		{[]string{"app"}, []string{"base", "lib", "ui", "app"}},
+		{[]string{"ui", "lib"}, []string{"base", "lib", "ui"}},
+		{[]string{"solo", "lib"}, []string{"base", "lib", "solo"}},
+		{[]string{"app", "base"}, []string{"base", "lib", "ui", "app"}},
+		{[]string{"wayland"}, []string{"wayland"}},
+	}
+	for _, tt := range tests {
+		order, err := InstallOrder(tt.ids)
+		if err != nil {
+			t.Errorf("InstallOrder(%v): %v", tt.ids, err)
+			continue
+		}
+		if got := ids(order); !slices.Equal(got, tt.want) {
+			t.Errorf("InstallOrder(%v) = %v, want %v", tt.ids, got, tt.want)
+		}
+	}
+	if _, err := InstallOrder([]string{"missing"}); err == nil {
+		t.Error("unknown bundle was not reported")
+	}
+}
+
+func TestMissingRequirements(t *testing.T) {
+	home := loadGraph(t)
+	tests := []struct {
+		installed []string
+		want      []string
+	}{
+		{nil, []string{"base", "lib", "ui"}},
+		{[]string{"base"}, []string{"lib", "ui"}},
+		{[]string{"base", "ui"}, []string{"lib"}},
+		{[]string{"base", "lib", "ui"}, []string{}},
+	}
+	for _, tt := range tests {
+		install(t, home, tt.installed...)
+		missing, err := MissingRequirements(find(t, "app"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := ids(missing); !slices.Equal(got, tt.want) {
+			t.Errorf("installed %v: missing %v, want %v", tt.installed, got, tt.want)
+		}
+	}
+}
+
+func TestDependents(t *testing.T) {
+	home := loadGraph(t)
+	install(t, home, "base", "lib")
+	if got := ids(Dependents("base")); !slices.Equal(got, []string{"lib"}) {
+		t.Errorf("Dependents(base) = %v, want [lib]; ui isn't installed", got)
+	}
+	if got := ids(Dependents("lib")); len(got) != 0 {
+		t.Errorf("Dependents(lib) = %v, want none", got)
+	}
+	install(t, home, "ui", "app")
+	if got := ids(Dependents("base")); !slices.Equal(got, []string{"lib", "ui"}) {
+		t.Errorf("Dependents(base) = %v, want [lib ui]", got)
+	}
+	if got := ids(Dependents("lib")); !slices.Equal(got, []string{"app"}) {
+		t.Errorf("Dependents(lib) = %v, want [app]", got)
+	}
+	if got := ids(Dependents("app")); len(got) != 0 {
+		t.Errorf("Dependents(app) = %v, want none", got)
+	}
+}
+
+func TestInstalledConflicts(t *testing.T) {
+	home := loadGraph(t)
+	x11, wayland := find(t, "x11"), find(t, "wayland")
+	if got := InstalledConflicts(x11); len(got) != 0 {
+		t.Errorf("nothing installed: conflicts %v", ids(got))
+	}
+
+	// x11 declares the conflict; it holds from either side.
+	install(t, home, "wayland")
+	if got := ids(InstalledConflicts(x11)); !slices.Equal(got, []string{"wayland"}) {
+		t.Errorf("x11 with wayland installed: conflicts %v, want [wayland]", got)
+	}
+	if err := os.Remove(filepath.Join(home, ".wayland")); err != nil {
+		t.Fatal(err)
+	}
+	install(t, home, "x11")
+	if got := ids(InstalledConflicts(wayland)); !slices.Equal(got, []string{"x11"}) {
+		t.Errorf("wayland with x11 installed: conflicts %v, want [x11]", got)
+	}
+	if got := InstalledConflicts(x11); len(got) != 0 {
+		t.Errorf("x11 conflicts with itself: %v", ids(got))
+	}
+	if got := InstalledConflicts(find(t, "solo")); len(got) != 0 {
+		t.Errorf("solo conflicts with %v", ids(got))
+	}
+}
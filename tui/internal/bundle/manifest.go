@@ -0,0 +1,271 @@
+package bundle
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// ManifestFile is the per-bundle manifest name inside scripts/<id>/.
+const ManifestFile = "bundle.toml"
+
+// manifest mirrors the on-disk bundle.toml schema.
+type manifest struct {
+	Name        string            `toml:"name"`
+	Description string            `toml:"description"`
+	Platform    string            `toml:"platform"`
+	Order       int               `toml:"order"`
+	Markers     []string          `toml:"markers"`
+	Links       []string          `toml:"links"`
+	Copies      map[string]string `toml:"copies"`
+	AutoSync    bool              `toml:"auto_sync"`
+	Requires    []string          `toml:"requires"`
+	Conflicts   []string          `toml:"conflicts"`
+	DryRun      bool              `toml:"dry_run"`
+	Touches     []string          `toml:"touches"`
+}
+
+// ManifestError reports a problem with a single bundle manifest.
+type ManifestError struct {
+	ID   string // The bundle's directory name
+	Path string
+	Err  error
+}
+
+func (e *ManifestError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Path, e.Err)
+}
+
+func (e *ManifestError) Unwrap() error { return e.Err }
+
+// Discover reads every scripts/<id>/bundle.toml under rootDir.
+// Directories without a manifest (e.g. scripts/shared) are skipped.
+// A bundle with a bad manifest is left out, along with any bundle that
+// requires it, and the rest are returned; the problems come back together
+// in the error, as *ManifestError values, so they can be fixed in one pass.
+func Discover(rootDir string) ([]Bundle, error) {
+	bundles, bad, err := discover(rootDir)
+	if err != nil {
+		return nil, err
+	}
+	if len(bad) == 0 {
+		return bundles, nil
+	}
+	errs := make([]error, len(bad))
+	for i, e := range bad {
+		errs[i] = e
+	}
+	return bundles, errors.Join(errs...)
+}
+
+// discover is Discover with the manifest errors kept apart from a failure
+// to read scripts/ at all.
+func discover(rootDir string) ([]Bundle, []*ManifestError, error) {
+	if abs, err := filepath.Abs(rootDir); err == nil {
+		rootDir = abs
+	}
+	scriptsDir := filepath.Join(rootDir, "scripts")
+	entries, err := os.ReadDir(scriptsDir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read %s: %w", scriptsDir, err)
+	}
+
+	var bundles []Bundle
+	var errs []*ManifestError
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		path := filepath.Join(scriptsDir, e.Name(), ManifestFile)
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		b, err := parseManifest(path, e.Name())
+		if err != nil {
+			errs = append(errs, &ManifestError{ID: e.Name(), Path: path, Err: err})
+			continue
+		}
+		bundles = append(bundles, b)
+	}
+	sortBundles(bundles)
+
+	// Dropping a bundle can break the ones that require it, so repeat until
+	// what is left holds together.
+	for {
+		bad := validateRelations(bundles, errs, scriptsDir)
+		if len(bad) == 0 {
+			bad = findCycles(bundles, scriptsDir)
+		}
+		if len(bad) == 0 {
+			break
+		}
+		errs = append(errs, bad...)
+		bundles = slices.DeleteFunc(bundles, func(b Bundle) bool {
+			return slices.ContainsFunc(bad, func(e *ManifestError) bool { return e.ID == b.ID })
+		})
+	}
+	return bundles, errs, nil
+}
+
+// findCycles reports every bundle that can't be installed because its
+// requirements loop back on themselves.
+func findCycles(bundles []Bundle, scriptsDir string) []*ManifestError {
+	var errs []*ManifestError
+	for _, b := range bundles {
+		if _, err := installOrder(bundles, []string{b.ID}); err != nil {
+			errs = append(errs, &ManifestError{ID: b.ID, Path: filepath.Join(scriptsDir, b.ID, ManifestFile), Err: err})
+		}
+	}
+	return errs
+}
+
+// parseManifest decodes and validates a single manifest file.
+func parseManifest(path, id string) (Bundle, error) {
+	var m manifest
+	md, err := toml.DecodeFile(path, &m)
+	if err != nil {
+		return Bundle{}, err
+	}
+	if undecoded := md.Undecoded(); len(undecoded) > 0 {
+		keys := make([]string, len(undecoded))
+		for i, k := range undecoded {
+			keys[i] = k.String()
+		}
+		return Bundle{}, fmt.Errorf("unknown keys: %s", strings.Join(keys, ", "))
+	}
+
+	if err := ValidateID(id); err != nil {
+		return Bundle{}, err
+	}
+	if strings.TrimSpace(m.Name) == "" {
+		return Bundle{}, errors.New("name is required")
+	}
+	if strings.TrimSpace(m.Description) == "" {
+		return Bundle{}, errors.New("description is required")
+	}
+	if len(m.Markers)+len(m.Links)+len(m.Copies) == 0 {
+		return Bundle{}, errors.New("at least one of markers, links or copies is required")
+	}
+	tracked := append(append([]string{}, m.Markers...), m.Links...)
+	for dst := range m.Copies {
+		tracked = append(tracked, dst)
+	}
+	for _, mk := range append(tracked, m.Touches...) {
+		if err := validateMarker(mk); err != nil {
+			return Bundle{}, err
+		}
+	}
+
+	dir := filepath.Dir(path)
+	for dst, src := range m.Copies {
+		if err := validateMarker(src); err != nil {
+			return Bundle{}, fmt.Errorf("copies[%q]: source %w", dst, err)
+		}
+		if _, err := os.Stat(filepath.Join(dir, src)); err != nil {
+			return Bundle{}, fmt.Errorf("copies[%q]: source %s not found", dst, src)
+		}
+	}
+	for _, script := range []string{"install.sh", "uninstall.sh"} {
+		if _, err := os.Stat(filepath.Join(dir, script)); err != nil {
+			return Bundle{}, fmt.Errorf("missing %s", script)
+		}
+	}
+
+	return Bundle{
+		ID:             id,
+		Name:           m.Name,
+		Description:    m.Description,
+		PlatformSuffix: m.Platform,
+		Dir:            dir,
+		Markers:        m.Markers,
+		Links:          m.Links,
+		Copies:         m.Copies,
+		AutoSync:       m.AutoSync,
+		Requires:       m.Requires,
+		Conflicts:      m.Conflicts,
+		DryRun:         m.DryRun,
+		Touches:        m.Touches,
+		Order:          m.Order,
+	}, nil
+}
+
+// ValidateID rejects bundle IDs that could escape scripts/ when joined into a path.
+func ValidateID(id string) error {
+	if id == "" || id == "." || id == ".." || strings.ContainsAny(id, `/\`) {
+		return fmt.Errorf("invalid bundle ID: %q", id)
+	}
+	return nil
+}
+
+// validateMarker ensures a tracked path is clean and relative.
+func validateMarker(mk string) error {
+	if mk == "" || filepath.IsAbs(mk) {
+		return fmt.Errorf("path %q must be relative", mk)
+	}
+	for _, part := range strings.Split(filepath.ToSlash(mk), "/") {
+		if part == ".." {
+			return fmt.Errorf("path %q must not contain ..", mk)
+		}
+	}
+	return nil
+}
+
+// validateRelations checks that every requires/conflicts entry names another
+// known bundle and that no bundle both requires and conflicts with the same one.
+// Conflicts with a bundle whose manifest was rejected (in broken) are
+// harmless; requiring one is not.
+func validateRelations(bundles []Bundle, broken []*ManifestError, scriptsDir string) []*ManifestError {
+	known := make(map[string]bool, len(bundles))
+	for _, b := range bundles {
+		known[b.ID] = true
+	}
+	isBroken := func(id string) bool {
+		return slices.ContainsFunc(broken, func(e *ManifestError) bool { return e.ID == id })
+	}
+	var errs []*ManifestError
+	for _, b := range bundles {
+		path := filepath.Join(scriptsDir, b.ID, ManifestFile)
+		fail := func(err error) { errs = append(errs, &ManifestError{ID: b.ID, Path: path, Err: err}) }
+		required := make(map[string]bool, len(b.Requires))
+		for _, dep := range b.Requires {
+			switch {
+			case dep == b.ID:
+				fail(errors.New("bundle cannot require itself"))
+			case isBroken(dep):
+				fail(fmt.Errorf("requires bundle %q, which is invalid", dep))
+			case !known[dep]:
+				fail(fmt.Errorf("requires unknown bundle %q", dep))
+			}
+			required[dep] = true
+		}
+		for _, c := range b.Conflicts {
+			switch {
+			case c == b.ID:
+				fail(errors.New("bundle cannot conflict with itself"))
+			case isBroken(c):
+			case !known[c]:
+				fail(fmt.Errorf("conflicts with unknown bundle %q", c))
+			case required[c]:
+				fail(fmt.Errorf("both requires and conflicts with %q", c))
+			}
+		}
+	}
+	return errs
+}
+
+// ScriptPath returns the path of scripts/<id>/<action>.sh under rootDir.
+// Only "install" and "uninstall" are valid actions.
+func ScriptPath(rootDir, id, action string) (string, error) {
+	if err := ValidateID(id); err != nil {
+		return "", err
+	}
+	if action != "install" && action != "uninstall" {
+		return "", fmt.Errorf("invalid action: %s", action)
+	}
+	return filepath.Join(rootDir, "scripts", id, action+".sh"), nil
+}
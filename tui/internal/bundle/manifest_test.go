@@ -0,0 +1,79 @@
+package bundle
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// writeBundle creates scripts/<id>/ with both scripts and the manifest.
+func writeBundle(t *testing.T, root, id, manifest string) {
+	t.Helper()
+	dir := filepath.Join(root, "scripts", id)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{ManifestFile: manifest, "install.sh": "", "uninstall.sh": ""}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestDiscoverSkipsInvalid(t *testing.T) {
+	root := t.TempDir()
+	const base = "description = \"d\"\nmarkers = [\".x\"]\n"
+	writeBundle(t, root, "good", "name = \"Good\"\n"+base)
+	writeBundle(t, root, "nodesc", "name = \"No description\"\nmarkers = [\".x\"]\n")
+	writeBundle(t, root, "typo", "name = \"Typo\"\n"+base+"requirez = []\n")
+	writeBundle(t, root, "needsbroken", "name = \"Needs\"\n"+base+"requires = [\"nodesc\"]\n")
+	writeBundle(t, root, "chain", "name = \"Chain\"\n"+base+"requires = [\"needsbroken\"]\n")
+	writeBundle(t, root, "ping", "name = \"Ping\"\n"+base+"requires = [\"pong\"]\n")
+	writeBundle(t, root, "pong", "name = \"Pong\"\n"+base+"requires = [\"ping\"]\n")
+	writeBundle(t, root, "rival", "name = \"Rival\"\n"+base+"conflicts = [\"nodesc\"]\n")
+	if err := os.MkdirAll(filepath.Join(root, "scripts", "shared"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	bundles, err := Discover(root)
+	var ids []string
+	for _, b := range bundles {
+		ids = append(ids, b.ID)
+	}
+	if want := []string{"good", "rival"}; !slices.Equal(ids, want) {
+		t.Errorf("loaded %v, want %v", ids, want)
+	}
+	var me *ManifestError
+	if !errors.As(err, &me) {
+		t.Fatalf("err = %v, want manifest errors", err)
+	}
+
+	if err := Load(root); err != nil {
+		t.Fatal(err)
+	}
+	var broken []string
+	for _, e := range Broken() {
+		if !slices.Contains(broken, e.ID) {
+			broken = append(broken, e.ID)
+		}
+	}
+	slices.Sort(broken)
+	if want := []string{"chain", "needsbroken", "nodesc", "ping", "pong", "typo"}; !slices.Equal(broken, want) {
+		t.Errorf("broken %v, want %v", broken, want)
+	}
+	if Problem("needsbroken") == nil || Problem("good") != nil {
+		t.Errorf("Problem: needsbroken = %v, good = %v", Problem("needsbroken"), Problem("good"))
+	}
+	if _, ok := Find("good"); !ok {
+		t.Error("valid bundle not registered")
+	}
+}
+
+func TestLoadUnreadable(t *testing.T) {
+	if err := Load(t.TempDir()); err == nil {
+		t.Error("missing scripts/ was not reported")
+	}
+}
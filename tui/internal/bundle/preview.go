@@ -0,0 +1,90 @@
+package bundle
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// previewTimeout bounds a dry run; scripts skip network and sudo in this mode.
+const previewTimeout = 2 * time.Minute
+
+// Change is one planned change reported by install.sh in dry-run mode
+// (see lib/dryrun.sh for the kinds).
+type Change struct {
+	Kind   string `json:"kind"`
+	Detail string `json:"detail"`
+}
+
+// PreviewResult is the outcome of Preview.
+type PreviewResult struct {
+	Changes []Change
+	Output  string // Combined script output, shown when the dry run fails
+}
+
+// Preview runs the bundle's install.sh with MYPCTOOLS_DRY_RUN=1 and collects
+// the changes it would make. Only bundles whose manifest sets dry_run can be
+// previewed; other scripts may still write to disk.
+func Preview(rootDir string, b Bundle) (PreviewResult, error) {
+	if !b.DryRun {
+		return PreviewResult{}, fmt.Errorf("%s does not support preview", b.Name)
+	}
+	script, err := ScriptPath(rootDir, b.ID, "install")
+	if err != nil {
+		return PreviewResult{}, err
+	}
+
+	planFile, err := os.CreateTemp("", "mypctools-plan-*")
+	if err != nil {
+		return PreviewResult{}, err
+	}
+	planFile.Close()
+	defer os.Remove(planFile.Name())
+
+	ctx, cancel := context.WithTimeout(context.Background(), previewTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "bash", script)
+	cmd.Env = append(os.Environ(), "MYPCTOOLS_DRY_RUN=1", "MYPCTOOLS_PLAN_FILE="+planFile.Name())
+	out, runErr := cmd.CombinedOutput()
+
+	changes, err := readPlan(planFile.Name())
+	if err != nil {
+		return PreviewResult{Output: string(out)}, err
+	}
+	result := PreviewResult{Changes: changes, Output: string(out)}
+	if runErr != nil {
+		return result, fmt.Errorf("dry run failed: %w", runErr)
+	}
+	return result, nil
+}
+
+// readPlan parses "kind<TAB>detail" lines, dropping exact repeats
+// (e.g. two helpers both creating ~/.config).
+func readPlan(path string) ([]Change, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var changes []Change
+	seen := make(map[Change]bool)
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		kind, detail, ok := strings.Cut(sc.Text(), "\t")
+		if !ok || kind == "" {
+			continue
+		}
+		c := Change{Kind: kind, Detail: detail}
+		if seen[c] {
+			continue
+		}
+		seen[c] = true
+		changes = append(changes, c)
+	}
+	return changes, sc.Err()
+}
@@ -1,77 +1,94 @@
 package bundle
 
+import (
+	"slices"
+	"sort"
+	"sync"
+)
+
 // Bundle describes a script bundle that can be installed/uninstalled.
 type Bundle struct {
-	ID             string // Directory name under scripts/
-	Name           string // Display name
-	Description    string // Short description
-	PlatformSuffix string // Platform hint shown in the list (e.g. "arch", "hyprland")
-	MarkerPath     string // Path relative to $HOME that indicates installation
-	AutoSync       bool   // Re-run install.sh automatically after a repo update (config-only bundles only)
+	ID             string            // Directory name under scripts/
+	Name           string            // Display name
+	Description    string            // Short description
+	PlatformSuffix string            // Platform hint shown in the list (e.g. "arch", "hyprland")
+	Dir            string            // Absolute path of scripts/<id>
+	Markers        []string          // Paths relative to $HOME that must exist
+	Links          []string          // Paths relative to $HOME that must be symlinks into Dir (or scripts/shared)
+	Copies         map[string]string // $HOME-relative path → source relative to Dir; compared by hash
+	AutoSync       bool              // Re-run install.sh automatically after a repo update (config-only bundles only)
+	Requires       []string          // IDs of bundles that must be installed first
+	Conflicts      []string          // IDs of bundles that should not be installed alongside this one
+	DryRun         bool              // install.sh honours MYPCTOOLS_DRY_RUN, so it can be previewed
+	Touches        []string          // Other $HOME-relative paths install.sh edits; snapshotted for rollback
+	Order          int               // Display order (lower first; ties sort by name)
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   []Bundle
+	broken     []*ManifestError
+)
+
+// Load discovers and validates every scripts/<id>/bundle.toml under rootDir
+// and makes the result available through All. Call once at startup.
+// Bundles with an invalid manifest are left out and reported by Broken; the
+// rest still load. An error only means scripts/ couldn't be read, and the
+// registry is then left unchanged.
+func Load(rootDir string) error {
+	bundles, bad, err := discover(rootDir)
+	if err != nil {
+		return err
+	}
+	registryMu.Lock()
+	registry, broken = bundles, bad
+	registryMu.Unlock()
+	return nil
+}
+
+// Broken returns the manifests the last Load rejected, in the order found.
+func Broken() []*ManifestError {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	return slices.Clone(broken)
+}
+
+// Problem returns why bundle id was left out by Load, or nil.
+func Problem(id string) error {
+	for _, e := range Broken() {
+		if e.ID == id {
+			return e
+		}
+	}
+	return nil
 }
 
 // All returns the list of all available bundles in display order.
+// Returns nil until Load has succeeded.
 func All() []Bundle {
-	return []Bundle{
-		{
-			ID:          "litebash",
-			Name:        "LiteBash",
-			Description: "bash with modern CLI tools (eza, bat, ripgrep, fd, zoxide)",
-			MarkerPath:  ".local/share/litebash/litebash.sh",
-		},
-		{
-			ID:          "litezsh",
-			Name:        "LiteZsh",
-			Description: "zsh with syntax highlighting and autosuggestions",
-			MarkerPath:  ".local/share/litezsh/litezsh.zsh",
-		},
-		{
-			ID:          "alacritty",
-			Name:        "Alacritty",
-			Description: "X11/Wayland terminal config",
-			MarkerPath:  ".config/alacritty/alacritty.toml",
-			AutoSync:    true,
-		},
-		{
-			ID:          "kitty",
-			Name:        "Kitty",
-			Description: "X11/Wayland terminal config",
-			MarkerPath:  ".config/kitty/kitty.conf",
-			AutoSync:    true,
-		},
-		{
-			ID:          "fastfetch",
-			Name:        "Fastfetch",
-			Description: "tree-style layout with nerd font icons",
-			MarkerPath:  ".config/fastfetch/config.jsonc",
-			AutoSync:    true,
-		},
-		{
-			ID:             "screensaver",
-			Name:           "Screensaver",
-			Description:    "terminal screensaver via hypridle + tte",
-			PlatformSuffix: "hyprland",
-			MarkerPath:     ".local/bin/mypctools-screensaver-launch",
-		},
-		{
-			ID:             "gnome-ubuntu",
-			Name:           "GNOME Ubuntu",
-			Description:    "Ubuntu GNOME defaults for Arch",
-			PlatformSuffix: "arch",
-			MarkerPath:     ".local/share/gnome-ubuntu/installed",
-		},
-		{
-			ID:          "claude",
-			Name:        "Claude",
-			Description: "Claude Code skills and statusline",
-			MarkerPath:  ".claude/statusline.sh",
-			AutoSync:    true,
-		},
-		{
-			ID:          "spicetify",
-			Name:        "Spicetify",
-			Description: "StarryNight theme for Spotify",
-			MarkerPath:  ".config/spicetify/config-xpui.ini",
-		},
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	out := make([]Bundle, len(registry))
+	copy(out, registry)
+	return out
+}
+
+// Find returns the bundle with the given ID.
+func Find(id string) (Bundle, bool) {
+	for _, b := range All() {
+		if b.ID == id {
+			return b, true
+		}
 	}
+	return Bundle{}, false
+}
+
+// sortBundles orders bundles by Order, then Name.
+func sortBundles(bundles []Bundle) {
+	sort.SliceStable(bundles, func(i, j int) bool {
+		if bundles[i].Order != bundles[j].Order {
+			return bundles[i].Order < bundles[j].Order
+		}
+		return bundles[i].Name < bundles[j].Name
+	})
 }
@@ -0,0 +1,358 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+
+	"github.com/reisset/mypctools/tui/internal/bundle"
+	"github.com/reisset/mypctools/tui/internal/logging"
+)
+
+// Exit codes returned by headless commands.
+const (
+	ExitOK       = 0 // Success (or, for "status <id>", installed)
+	ExitFailure  = 1 // Script failed (or, for "status <id>", not installed)
+	ExitUsage    = 2 // Bad arguments
+	ExitNotFound = 3 // Unknown bundle ID
+	ExitRefused  = 4 // Blocked by a conflict or installed dependents (override with --force)
+	ExitInvalid  = 5 // The bundle's manifest is invalid, or it requires one that is
+)
+
+const bundleUsage = `Usage: mypctools bundle <command> [id] [--json]
+
+Commands:
+  list               List all bundles
+  status [id]        Show install status (exit 0 = installed, 1 = not installed)
+  install <id>       Run install.sh for missing dependencies, then the bundle
+  uninstall <id>     Run the bundle's uninstall.sh
+  reinstall <id>     Re-run the bundle's install.sh (dependencies too if missing)
+  preview <id>       Dry-run install.sh and list the changes it would make
+
+Options:
+  --json             Machine-readable output on stdout (script output goes to stderr)
+  --force            Install despite conflicts / uninstall despite dependents
+`
+
+// bundleInfo is the JSON shape for list/status output.
+type bundleInfo struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Platform    string   `json:"platform,omitempty"`
+	AutoSync    bool     `json:"auto_sync"`
+	Requires    []string `json:"requires,omitempty"`
+	Installed   bool     `json:"installed"`
+	State       string   `json:"state"`
+	Problems    []string `json:"problems,omitempty"`
+}
+
+// actionResult is the JSON shape for install/uninstall/reinstall output.
+// Steps lists every bundle run, dependencies first.
+type actionResult struct {
+	ID     string       `json:"id"`
+	Action string       `json:"action"`
+	OK     bool         `json:"ok"`
+	Error  string       `json:"error,omitempty"`
+	Steps  []stepResult `json:"steps,omitempty"`
+}
+
+// previewResult is the JSON shape for preview output.
+type previewResult struct {
+	ID      string          `json:"id"`
+	OK      bool            `json:"ok"`
+	Error   string          `json:"error,omitempty"`
+	Changes []bundle.Change `json:"changes"`
+}
+
+type stepResult struct {
+	ID    string `json:"id"`
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+// RunBundle executes "mypctools bundle ..." and returns the process exit code.
+// Bundles must already be loaded via bundle.Load. Invalid manifests only
+// fail the commands that name their bundle; list and status warn about them.
+func RunBundle(rootDir string, args []string, stdout, stderr io.Writer) int {
+	var asJSON, force bool
+	var rest []string
+	for _, a := range args {
+		switch a {
+		case "--json":
+			asJSON = true
+		case "--force":
+			force = true
+		case "--help", "-h":
+			fmt.Fprint(stdout, bundleUsage)
+			return ExitOK
+		default:
+			rest = append(rest, a)
+		}
+	}
+	if len(rest) == 0 {
+		fmt.Fprint(stderr, bundleUsage)
+		return ExitUsage
+	}
+
+	sub, rest := rest[0], rest[1:]
+	switch sub {
+	case "list":
+		if len(rest) != 0 {
+			return usageError(stderr, "list takes no arguments")
+		}
+		return listBundles(stdout, stderr, asJSON)
+	case "status":
+		if len(rest) > 1 {
+			return usageError(stderr, "status takes at most one bundle ID")
+		}
+		if len(rest) == 1 {
+			return bundleStatus(rest[0], stdout, stderr, asJSON)
+		}
+		return listBundles(stdout, stderr, asJSON)
+	case "install", "uninstall", "reinstall":
+		if len(rest) != 1 {
+			return usageError(stderr, sub+" requires exactly one bundle ID")
+		}
+		return runAction(rootDir, rest[0], sub, stdout, stderr, asJSON, force)
+	case "preview":
+		if len(rest) != 1 {
+			return usageError(stderr, "preview requires exactly one bundle ID")
+		}
+		return previewBundle(rootDir, rest[0], stdout, stderr, asJSON)
+	default:
+		return usageError(stderr, "unknown command: "+sub)
+	}
+}
+
+func usageError(stderr io.Writer, msg string) int {
+	fmt.Fprintf(stderr, "Error: %s\n\n%s", msg, bundleUsage)
+	return ExitUsage
+}
+
+// notFound reports a bundle that isn't loaded: unknown, or left out because
+// of an invalid manifest.
+func notFound(id string, stderr io.Writer) int {
+	if err := bundle.Problem(id); err != nil {
+		fmt.Fprintf(stderr, "Invalid bundle manifest: %v\n", err)
+		return ExitInvalid
+	}
+	fmt.Fprintf(stderr, "Unknown bundle: %s\n", id)
+	return ExitNotFound
+}
+
+// warnBroken lists the bundles left out because of invalid manifests.
+func warnBroken(stderr io.Writer) {
+	for _, err := range bundle.Broken() {
+		fmt.Fprintf(stderr, "Warning: skipped invalid bundle manifest: %v\n", err)
+	}
+}
+
+func toInfo(b bundle.Bundle) bundleInfo {
+	st := bundle.Detect(&b)
+	return bundleInfo{
+		ID:          b.ID,
+		Name:        b.Name,
+		Description: b.Description,
+		Platform:    b.PlatformSuffix,
+		AutoSync:    b.AutoSync,
+		Requires:    b.Requires,
+		Installed:   st.State != bundle.StateMissing,
+		State:       st.State.String(),
+		Problems:    st.Problems,
+	}
+}
+
+func listBundles(stdout, stderr io.Writer, asJSON bool) int {
+	warnBroken(stderr)
+	bundles := bundle.All()
+	infos := make([]bundleInfo, len(bundles))
+	for i, b := range bundles {
+		infos[i] = toInfo(b)
+	}
+
+	if asJSON {
+		return writeJSON(stdout, infos)
+	}
+
+	for _, info := range infos {
+		state := "-"
+		if info.Installed {
+			state = info.State
+		}
+		desc := info.Description
+		if info.Platform != "" {
+			desc += " [" + info.Platform + "]"
+		}
+		fmt.Fprintf(stdout, "%-14s %-10s %s\n", info.ID, state, desc)
+	}
+	return ExitOK
+}
+
+func bundleStatus(id string, stdout, stderr io.Writer, asJSON bool) int {
+	b, ok := bundle.Find(id)
+	if !ok {
+		return notFound(id, stderr)
+	}
+	info := toInfo(b)
+
+	if asJSON {
+		if code := writeJSON(stdout, info); code != ExitOK {
+			return code
+		}
+	} else {
+		fmt.Fprintf(stdout, "%s: %s\n", info.ID, info.State)
+		for _, p := range info.Problems {
+			fmt.Fprintf(stdout, "  %s\n", p)
+		}
+	}
+
+	if !info.Installed {
+		return ExitFailure
+	}
+	return ExitOK
+}
+
+func runAction(rootDir, id, action string, stdout, stderr io.Writer, asJSON, force bool) int {
+	b, ok := bundle.Find(id)
+	if !ok {
+		return notFound(id, stderr)
+	}
+
+	script := "install"
+	plan := []bundle.Bundle{b}
+	if action == "uninstall" {
+		script = "uninstall"
+		if deps := bundle.Dependents(b.ID); len(deps) > 0 && !force {
+			return refuse(stdout, stderr, asJSON, b.ID, action,
+				fmt.Sprintf("required by installed: %s", bundle.Names(deps)))
+		}
+	} else {
+		missing, err := bundle.MissingRequirements(b)
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return ExitFailure
+		}
+		plan = append(missing, b)
+		if !force {
+			for _, p := range plan {
+				if c := bundle.InstalledConflicts(p); len(c) > 0 {
+					return refuse(stdout, stderr, asJSON, b.ID, action,
+						fmt.Sprintf("%s conflicts with installed: %s", p.Name, bundle.Names(c)))
+				}
+			}
+		}
+	}
+
+	// Keep stdout clean for JSON consumers.
+	scriptOut := stdout
+	if asJSON {
+		scriptOut = stderr
+	}
+
+	result := actionResult{ID: b.ID, Action: action, OK: true}
+	for _, p := range plan {
+		scriptPath, err := bundle.ScriptPath(rootDir, p.ID, script)
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return ExitUsage
+		}
+
+		cmd := exec.Command("bash", scriptPath)
+		cmd.Env = os.Environ()
+		cmd.Stdin = os.Stdin
+		cmd.Stdout = scriptOut
+		cmd.Stderr = stderr
+		runErr := cmd.Run()
+
+		step := stepResult{ID: p.ID, OK: runErr == nil}
+		if runErr != nil {
+			step.Error = runErr.Error()
+			result.OK = false
+			result.Error = fmt.Sprintf("%s: %v", p.ID, runErr)
+			logging.LogAction(fmt.Sprintf("Script %s %s failed (cli)", p.Name, script)) //nolint:errcheck
+		} else {
+			logging.LogAction(fmt.Sprintf("Script %s %s completed (cli)", p.Name, script)) //nolint:errcheck
+		}
+		result.Steps = append(result.Steps, step)
+
+		if !asJSON {
+			if runErr != nil {
+				fmt.Fprintf(stderr, "%s %s failed: %v\n", p.Name, action, runErr)
+			} else {
+				fmt.Fprintf(stdout, "%s %s completed\n", p.Name, action)
+			}
+		}
+		if runErr != nil {
+			break
+		}
+	}
+
+	if asJSON {
+		if code := writeJSON(stdout, result); code != ExitOK {
+			return code
+		}
+	}
+	if !result.OK {
+		return ExitFailure
+	}
+	return ExitOK
+}
+
+func previewBundle(rootDir, id string, stdout, stderr io.Writer, asJSON bool) int {
+	b, ok := bundle.Find(id)
+	if !ok {
+		return notFound(id, stderr)
+	}
+
+	res, err := bundle.Preview(rootDir, b)
+	result := previewResult{ID: b.ID, OK: err == nil, Changes: res.Changes}
+	if result.Changes == nil {
+		result.Changes = []bundle.Change{}
+	}
+	if err != nil {
+		result.Error = err.Error()
+		fmt.Fprint(stderr, res.Output)
+	}
+
+	if asJSON {
+		if code := writeJSON(stdout, result); code != ExitOK {
+			return code
+		}
+	} else {
+		for _, c := range result.Changes {
+			fmt.Fprintf(stdout, "%-9s %s\n", c.Kind, c.Detail)
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+		} else if len(result.Changes) == 0 {
+			fmt.Fprintf(stdout, "%s: nothing to change\n", b.ID)
+		}
+	}
+	if err != nil {
+		return ExitFailure
+	}
+	return ExitOK
+}
+
+// refuse reports a blocked action and returns ExitRefused.
+func refuse(stdout, stderr io.Writer, asJSON bool, id, action, reason string) int {
+	if asJSON {
+		writeJSON(stdout, actionResult{ID: id, Action: action, Error: reason})
+	} else {
+		fmt.Fprintf(stderr, "Refusing to %s %s: %s (use --force to override)\n", action, id, reason)
+	}
+	return ExitRefused
+}
+
+func writeJSON(w io.Writer, v any) int {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return ExitFailure
+	}
+	return ExitOK
+}
@@ -13,16 +13,18 @@ type DistroType string
 const (
 	DistroArch    DistroType = "arch"
 	DistroDebian  DistroType = "debian"
+	DistroFedora  DistroType = "fedora"
+	DistroSUSE    DistroType = "suse"
 	DistroUnknown DistroType = "unknown"
 )
 
-// DistroInfo holds detected distribution details.
+// DistroInfo holds detected distribution details. Package operations live
+// in internal/pkgmgr, selected from Type.
 type DistroInfo struct {
-	Type       DistroType
-	Name       string
-	PkgMgr     string
-	PkgInstall string
-	PkgUpdate  string
+	Type   DistroType
+	ID     string // os-release ID (e.g. "opensuse-tumbleweed")
+	Name   string
+	PkgMgr string // Native package manager command
 }
 
 // knownIDs maps /etc/os-release ID to distro type.
@@ -39,6 +41,22 @@ var knownIDs = map[string]DistroType{
 	"linuxmint":   DistroDebian,
 	"elementary":  DistroDebian,
 	"zorin":       DistroDebian,
+
+	"fedora":      DistroFedora,
+	"rhel":        DistroFedora,
+	"centos":      DistroFedora,
+	"rocky":       DistroFedora,
+	"almalinux":   DistroFedora,
+	"nobara":      DistroFedora,
+	"ultramarine": DistroFedora,
+
+	"opensuse":            DistroSUSE,
+	"opensuse-tumbleweed": DistroSUSE,
+	"opensuse-leap":       DistroSUSE,
+	"opensuse-slowroll":   DistroSUSE,
+	"opensuse-microos":    DistroSUSE,
+	"sles":                DistroSUSE,
+	"sled":                DistroSUSE,
 }
 
 // DetectDistro parses /etc/os-release and returns distro info.
@@ -50,6 +68,7 @@ func DetectDistro() DistroInfo {
 	name := fields["NAME"]
 	idLike := strings.ToLower(fields["ID_LIKE"])
 
+	info.ID = id
 	if name != "" {
 		info.Name = strings.Trim(name, "\"")
 	}
@@ -60,6 +79,10 @@ func DetectDistro() DistroInfo {
 		info.Type = DistroArch
 	} else if isIDLikeMatch(idLike, "debian") || isIDLikeMatch(idLike, "ubuntu") {
 		info.Type = DistroDebian
+	} else if isIDLikeMatch(idLike, "fedora") || isIDLikeMatch(idLike, "rhel") {
+		info.Type = DistroFedora
+	} else if isIDLikeMatch(idLike, "suse") || isIDLikeMatch(idLike, "opensuse") {
+		info.Type = DistroSUSE
 	} else {
 		info.Type = detectByCommand()
 	}
@@ -67,12 +90,12 @@ func DetectDistro() DistroInfo {
 	switch info.Type {
 	case DistroArch:
 		info.PkgMgr = "pacman"
-		info.PkgInstall = "sudo pacman -S --noconfirm --needed"
-		info.PkgUpdate = "sudo pacman -Syu"
 	case DistroDebian:
 		info.PkgMgr = "apt"
-		info.PkgInstall = "sudo apt install -y"
-		info.PkgUpdate = "sudo apt update && sudo apt upgrade -y"
+	case DistroFedora:
+		info.PkgMgr = "dnf"
+	case DistroSUSE:
+		info.PkgMgr = "zypper"
 	}
 
 	return info
@@ -119,5 +142,11 @@ func detectByCommand() DistroType {
 	if _, err := exec.LookPath("apt"); err == nil {
 		return DistroDebian
 	}
+	if _, err := exec.LookPath("dnf"); err == nil {
+		return DistroFedora
+	}
+	if _, err := exec.LookPath("zypper"); err == nil {
+		return DistroSUSE
+	}
 	return DistroUnknown
 }
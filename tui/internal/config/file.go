@@ -0,0 +1,401 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// FileName is the user config under ~/.config/mypctools/.
+const FileName = "config.toml"
+
+// legacyFlags are the empty marker files that held preferences before
+// config.toml, relative to ~/.config/mypctools/. Load folds them in.
+var legacyFlags = []string{"nerd-font", "capture-output"}
+
+// TerminalThemes are the themes the terminal bundles ship; "" means ask
+// during install.
+var TerminalThemes = []string{"", "catppuccin-mocha", "tokyo-night", "hackthebox", "ubuntu"}
+
+// ServiceBackends are the ways the Service Manager can reach systemd: "auto"
+// uses D-Bus when the bus is reachable and systemctl otherwise.
+var ServiceBackends = []string{"auto", "systemctl"}
+
+// TerminalThemeEnv passes the configured theme to the terminal bundles'
+// install scripts.
+const TerminalThemeEnv = "MYPCTOOLS_TERMINAL_THEME"
+
+// File mirrors the on-disk config.toml schema.
+type File struct {
+	UI       UI              `toml:"ui"`
+	Terminal Terminal        `toml:"terminal"`
+	Services Services        `toml:"services"`
+	Update   Update          `toml:"update"`
+	Cleanup  []CleanupTarget `toml:"cleanup"`
+}
+
+// UI holds display preferences.
+type UI struct {
+	NerdFont      bool `toml:"nerd_font"`      // Nerd Font icons instead of ASCII
+	CaptureOutput bool `toml:"capture_output"` // Run commands in the in-TUI output pane
+	ToastSeconds  int  `toml:"toast_seconds"`  // How long toasts stay visible (1-10)
+}
+
+// Terminal holds preferences for the terminal bundles (kitty, alacritty).
+type Terminal struct {
+	Theme string `toml:"theme"` // One of TerminalThemes
+}
+
+// Services configures the Service Manager. The top-level lists are for
+// system units; [services.user] holds the same for user units.
+type Services struct {
+	Units
+	Backend string `toml:"backend"` // One of ServiceBackends
+	User    Units  `toml:"user"`
+}
+
+// Units lists the services of one systemd scope.
+type Units struct {
+	Known  []string `toml:"known"`  // Units listed by default
+	Pinned []string `toml:"pinned"` // Pinned from the service lists; shown first
+}
+
+// Scope returns the system units, or the user units when user is set.
+func (s *Services) Scope(user bool) *Units {
+	if user {
+		return &s.User
+	}
+	return &s.Units
+}
+
+// IsPinned reports whether the named service is pinned.
+func (u Units) IsPinned(name string) bool { return slices.Contains(u.Pinned, name) }
+
+// TogglePin pins or unpins the named service and reports whether it is now
+// pinned. Pinned is copied, never modified in place, so a config value
+// shared with another copy stays unchanged.
+func (u *Units) TogglePin(name string) bool {
+	if i := slices.Index(u.Pinned, name); i >= 0 {
+		u.Pinned = slices.Delete(slices.Clone(u.Pinned), i, i+1)
+		return false
+	}
+	u.Pinned = append(slices.Clone(u.Pinned), name)
+	return true
+}
+
+// Update configures where Pull Updates and the update check fetch from.
+type Update struct {
+	Remote string `toml:"remote"`
+	Branch string `toml:"branch"`
+}
+
+// Default returns the configuration used for keys config.toml leaves out.
+func Default() File {
+	return File{
+		UI: UI{ToastSeconds: 3},
+		Services: Services{
+			Backend: "auto",
+			Units: Units{Known: []string{
+				"docker", "ssh", "sshd", "bluetooth", "cups", "NetworkManager",
+				"avahi-daemon", "cron", "crond", "ufw", "firewalld",
+			}},
+			User: Units{Known: []string{
+				"pipewire", "pipewire-pulse", "wireplumber", "syncthing",
+				"hypridle", "gpg-agent", "ssh-agent",
+			}},
+		},
+		Update: Update{Remote: "origin", Branch: "main"},
+	}
+}
+
+// Upstream returns the remote-tracking ref updates come from ("origin/main").
+func (u Update) Upstream() string { return u.Remote + "/" + u.Branch }
+
+// ToastDuration returns how long toasts stay visible.
+func (u UI) ToastDuration() time.Duration { return time.Duration(u.ToastSeconds) * time.Second }
+
+// CleanupTarget is a user-defined System Cleanup category:
+//
+//	[[cleanup]]
+//	name = "Old screenshots"
+//	paths = ["~/Pictures/Screenshots/*.png"]
+//	older_than = "30d"
+type CleanupTarget struct {
+	Name      string   `toml:"name"`
+	Paths     []string `toml:"paths"`                // Globs; see validateCleanupPath
+	OlderThan string   `toml:"older_than,omitempty"` // Only matches untouched this long ("" = any age)
+}
+
+// Path returns ~/.config/mypctools/config.toml.
+func Path() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", "mypctools", FileName), nil
+}
+
+// Load reads and validates the config file, filling in defaults for missing
+// keys. A missing file is not an error. Legacy flag files are folded in by
+// MigrateFlags.
+func Load() (File, error) {
+	f := Default()
+	path, err := Path()
+	if err != nil {
+		return f, err
+	}
+	md, err := toml.DecodeFile(path, &f)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		f = Default()
+	case err != nil:
+		return Default(), fmt.Errorf("%s: %w", path, err)
+	default:
+		if undecoded := md.Undecoded(); len(undecoded) > 0 {
+			keys := make([]string, len(undecoded))
+			for i, k := range undecoded {
+				keys[i] = k.String()
+			}
+			return Default(), fmt.Errorf("%s: unknown keys: %s", path, strings.Join(keys, ", "))
+		}
+	}
+	if err := f.Validate(); err != nil {
+		return Default(), fmt.Errorf("%s: %w", path, err)
+	}
+	return f, nil
+}
+
+// Validate checks every setting, reporting all problems together.
+func (f File) Validate() error {
+	var errs []error
+	if s := f.UI.ToastSeconds; s < 1 || s > 10 {
+		errs = append(errs, fmt.Errorf("ui.toast_seconds must be between 1 and 10, got %d", s))
+	}
+	if !slices.Contains(TerminalThemes, f.Terminal.Theme) {
+		errs = append(errs, fmt.Errorf("terminal.theme %q must be one of %s", f.Terminal.Theme, strings.Join(TerminalThemes[1:], ", ")))
+	}
+	if !slices.Contains(ServiceBackends, f.Services.Backend) {
+		errs = append(errs, fmt.Errorf("services.backend %q must be one of %s", f.Services.Backend, strings.Join(ServiceBackends, ", ")))
+	}
+	units := []struct {
+		key   string
+		names []string
+	}{
+		{"services.known", f.Services.Known},
+		{"services.pinned", f.Services.Pinned},
+		{"services.user.known", f.Services.User.Known},
+		{"services.user.pinned", f.Services.User.Pinned},
+	}
+	for _, u := range units {
+		for _, name := range u.names {
+			if err := ValidateUnit(name); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", u.key, err))
+			}
+		}
+	}
+	if err := validateRef("update.remote", f.Update.Remote); err != nil {
+		errs = append(errs, err)
+	}
+	if err := validateRef("update.branch", f.Update.Branch); err != nil {
+		errs = append(errs, err)
+	}
+	for i, t := range f.Cleanup {
+		if err := t.validate(); err != nil {
+			errs = append(errs, fmt.Errorf("cleanup[%d]: %w", i, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ValidateUnit rejects service names that systemctl would misread.
+func ValidateUnit(name string) error {
+	if name == "" || strings.HasPrefix(name, "-") || strings.ContainsAny(name, "/ \t") {
+		return fmt.Errorf("invalid unit name %q", name)
+	}
+	return nil
+}
+
+// validateRef rejects git remote and branch names that could be read as flags.
+func validateRef(key, ref string) error {
+	if ref == "" || strings.HasPrefix(ref, "-") || strings.ContainsAny(ref, " \t:~^?*[\\") || strings.Contains(ref, "..") {
+		return fmt.Errorf("%s: invalid name %q", key, ref)
+	}
+	return nil
+}
+
+// Save validates f and writes it to config.toml atomically.
+func Save(f File) error {
+	if err := f.Validate(); err != nil {
+		return err
+	}
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	buf.WriteString("# mypctools settings — edited by System Setup → Settings.\n\n")
+	enc := toml.NewEncoder(&buf)
+	enc.Indent = ""
+	if err := enc.Encode(f); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// MigrateFlags turns legacy flag files into settings on f. The flags apply
+// to f even when saving fails; the files are then kept so the migration is
+// retried on the next run.
+func MigrateFlags(f *File) error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+	return migrateFlags(f, filepath.Dir(path))
+}
+
+// migrateFlags turns legacy flag files in dir into settings, saving the
+// config before removing them so a failed save loses nothing.
+func migrateFlags(f *File, dir string) error {
+	var found []string
+	for _, name := range legacyFlags {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			continue
+		}
+		found = append(found, name)
+		switch name {
+		case "nerd-font":
+			f.UI.NerdFont = true
+		case "capture-output":
+			f.UI.CaptureOutput = true
+		}
+	}
+	if len(found) == 0 {
+		return nil
+	}
+	if err := Save(*f); err != nil {
+		return fmt.Errorf("migrating %s: %w", strings.Join(found, ", "), err)
+	}
+	for _, name := range found {
+		os.Remove(filepath.Join(dir, name))
+	}
+	return nil
+}
+
+func (t CleanupTarget) validate() error {
+	if strings.TrimSpace(t.Name) == "" {
+		return errors.New("name is required")
+	}
+	if len(t.Paths) == 0 {
+		return errors.New("paths is required")
+	}
+	for _, p := range t.Paths {
+		if err := validateCleanupPath(p); err != nil {
+			return err
+		}
+	}
+	if t.OlderThan != "" {
+		if _, err := ParseAge(t.OlderThan); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cleanupTmpDirs and cleanupHomeDirs (relative to $HOME) are where cleanup
+// paths may match anything below. Elsewhere in $HOME a path needs two
+// literal directories; outside it, only these prefixes are allowed.
+var (
+	cleanupTmpDirs  = []string{"/tmp", "/var/tmp"}
+	cleanupHomeDirs = []string{".cache", "tmp"}
+)
+
+// validateCleanupPath rejects globs that could match a home directory, a
+// top-level directory or anything outside the allowed areas, since cleanup
+// deletes every match recursively.
+func validateCleanupPath(p string) error {
+	if !strings.HasPrefix(p, "/") && !strings.HasPrefix(p, "~/") {
+		return fmt.Errorf("path %q must be absolute or start with ~/", p)
+	}
+	if _, err := filepath.Match(p, ""); err != nil {
+		return fmt.Errorf("path %q: %w", p, err)
+	}
+	clean := filepath.Clean(ExpandHome(p))
+	parts := strings.Split(strings.TrimPrefix(clean, "/"), "/")
+	for _, part := range parts[:len(parts)-1] {
+		if strings.ContainsAny(part, "*?[") {
+			return fmt.Errorf("path %q: wildcards are only allowed in the last component", p)
+		}
+	}
+	under := func(dir string) bool { return strings.HasPrefix(clean, dir+"/") }
+	for _, dir := range cleanupTmpDirs {
+		if under(dir) {
+			return nil
+		}
+	}
+	home, err := os.UserHomeDir()
+	if err != nil || filepath.Clean(home) == "/" || !under(filepath.Clean(home)) {
+		return fmt.Errorf("path %q must be under ~/ or one of %s", p, strings.Join(cleanupTmpDirs, ", "))
+	}
+	rel := strings.Split(strings.TrimPrefix(clean, filepath.Clean(home)+"/"), "/")
+	for _, dir := range cleanupHomeDirs {
+		if under(filepath.Join(home, dir)) {
+			return nil
+		}
+	}
+	literal := 0
+	for _, part := range rel {
+		if !strings.ContainsAny(part, "*?[") {
+			literal++
+		}
+	}
+	if literal < 2 {
+		return fmt.Errorf("path %q is too broad: name at least two directories under ~/ (or use ~/.cache/ or ~/tmp/)", p)
+	}
+	return nil
+}
+
+// ExpandHome replaces a leading ~/ with the home directory.
+func ExpandHome(path string) string {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, rest)
+		}
+	}
+	return path
+}
+
+// ParseAge parses ages like "30d", "2w" or any time.ParseDuration value.
+func ParseAge(s string) (time.Duration, error) {
+	units := map[byte]time.Duration{'d': 24 * time.Hour, 'w': 7 * 24 * time.Hour}
+	if n := len(s); n > 1 {
+		if unit, ok := units[s[n-1]]; ok {
+			if v, err := strconv.Atoi(s[:n-1]); err == nil && v >= 0 {
+				return time.Duration(v) * unit, nil
+			}
+		}
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid age %q (use e.g. 30d, 2w or 12h)", s)
+	}
+	return d, nil
+}
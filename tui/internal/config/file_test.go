@@ -0,0 +1,89 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestValidateCleanupPath(t *testing.T) {
+	t.Setenv("HOME", "/home/u")
+	tests := []struct {
+		path string
+		ok   bool
+	}{
+		{"~/Pictures/Screenshots/*.png", true},
+		{"~/.cache/*", true},
+		{"~/tmp/build-*", true},
+		{"/tmp/*.log", true},
+		{"/var/tmp/foo", true},
+		{"~/", false},
+		{"~/*", false},
+		{"~/.config", false},
+		{"~/Downloads/*.iso", false},
+		{"~/*/cache", false},
+		{"/*", false},
+		{"/usr/*", false},
+		{"/home/u", false},
+		{"/tmp", false},
+		{"~/.cache/../*", false},
+		{"relative/path", false},
+		{"~/Pictures/[", false},
+	}
+	for _, tt := range tests {
+		err := validateCleanupPath(tt.path)
+		if (err == nil) != tt.ok {
+			t.Errorf("validateCleanupPath(%q) = %v, want ok=%v", tt.path, err, tt.ok)
+		}
+	}
+}
+
+func TestMigrateFlags(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	path, err := Path()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	flag := filepath.Join(dir, "nerd-font")
+	if err := os.WriteFile(flag, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// A directory in config.toml's place makes Save fail: the flag still
+	// applies and its file stays for the next attempt.
+	if err := os.Mkdir(path, 0755); err != nil {
+		t.Fatal(err)
+	}
+	f := Default()
+	if err := MigrateFlags(&f); err == nil {
+		t.Error("failed save was not reported")
+	}
+	if !f.UI.NerdFont {
+		t.Error("nerd-font flag not applied after a failed save")
+	}
+	if _, err := os.Stat(flag); err != nil {
+		t.Errorf("flag file removed after a failed save: %v", err)
+	}
+
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	f = Default()
+	if err := MigrateFlags(&f); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(flag); !os.IsNotExist(err) {
+		t.Errorf("flag file kept after migrating: %v", err)
+	}
+	saved, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !saved.UI.NerdFont {
+		t.Error("migrated nerd-font flag not saved")
+	}
+}
@@ -0,0 +1,337 @@
+// Package fssnap takes filesystem snapshots before system updates with
+// snapper, timeshift, or a plain read-only btrfs snapshot of /.
+//
+// Like pkgmgr, commands that need root are returned as *exec.Cmd so screens
+// can run them where sudo can prompt. Snapshots created by mypctools are
+// recorded in a ledger so they can be listed and pruned without root.
+package fssnap
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/reisset/mypctools/tui/internal/logging"
+)
+
+// ledgerName is the ledger of created snapshots under logging.DataDir.
+const ledgerName = "snapshots.jsonl"
+
+// btrfsDir holds raw btrfs snapshots.
+const btrfsDir = "/.mypctools-snapshots"
+
+// snapperConfig is the snapper config for the root filesystem.
+const snapperConfig = "/etc/snapper/configs/root"
+
+// btrfsMagic is the statfs type of a btrfs filesystem.
+const btrfsMagic = 0x9123683E
+
+// Tool is one snapshot backend.
+type Tool interface {
+	// Name is the command users know the tool by.
+	Name() string
+	// create returns a bash snippet that takes a snapshot described by desc.
+	create(desc string) string
+	// parseID finds the new snapshot's identifier in create's output.
+	parseID(output string) string
+	// Delete returns a command that removes the snapshot id.
+	Delete(id string) *exec.Cmd
+}
+
+// Entry is one snapshot mypctools created.
+type Entry struct {
+	Tool        string    `json:"tool"`
+	ID          string    `json:"id"` // Snapper number, timeshift name, or btrfs path
+	Description string    `json:"description"`
+	Time        time.Time `json:"time"`
+}
+
+// Detect returns the first available tool: snapper with a root config,
+// timeshift, or btrfs when / is a btrfs filesystem. Nil when none applies.
+func Detect() Tool {
+	if onPath("snapper") && exists(snapperConfig) {
+		return snapper{}
+	}
+	if onPath("timeshift") {
+		return timeshift{}
+	}
+	if onPath("btrfs") && rootIsBtrfs() {
+		return btrfs{}
+	}
+	return nil
+}
+
+// ByName returns the tool that created an entry.
+func ByName(name string) (Tool, bool) {
+	switch name {
+	case "snapper":
+		return snapper{}, true
+	case "timeshift":
+		return timeshift{}, true
+	case "btrfs":
+		return btrfs{}, true
+	}
+	return nil, false
+}
+
+// Pending is a snapshot being taken.
+type Pending struct {
+	tool    Tool
+	desc    string
+	outFile string // Copy of the create command's output
+	time    time.Time
+}
+
+// Create returns a command that takes a snapshot labelled after the run
+// ("mypctools: system update 2025-01-02 15:04"). Call Finish once it succeeds.
+func Create(t Tool, run string) (*exec.Cmd, *Pending, error) {
+	f, err := os.CreateTemp("", "mypctools-snapshot-*")
+	if err != nil {
+		return nil, nil, err
+	}
+	f.Close()
+	now := time.Now()
+	p := &Pending{
+		tool:    t,
+		desc:    fmt.Sprintf("mypctools: %s %s", run, now.Format("2006-01-02 15:04")),
+		outFile: f.Name(),
+		time:    now,
+	}
+	script := fmt.Sprintf("set -o pipefail\n{\n%s} 2>&1 | tee %s\n", t.create(p.desc), shellQuote(p.outFile))
+	return exec.Command("bash", "-c", script), p, nil
+}
+
+// Finish records the snapshot in the ledger.
+func (p *Pending) Finish() (Entry, error) {
+	defer p.Discard()
+	data, err := os.ReadFile(p.outFile)
+	if err != nil {
+		return Entry{}, err
+	}
+	id := p.tool.parseID(string(data))
+	if id == "" {
+		return Entry{}, fmt.Errorf("%s did not report the snapshot it created", p.tool.Name())
+	}
+	e := Entry{Tool: p.tool.Name(), ID: id, Description: p.desc, Time: p.time}
+	return e, appendLedger(e)
+}
+
+// Discard removes the temporary output file (nil-safe).
+func (p *Pending) Discard() {
+	if p != nil {
+		os.Remove(p.outFile)
+	}
+}
+
+// ledgerMu serialises ledger writes within this process.
+var ledgerMu sync.Mutex
+
+func ledgerPath() (string, error) {
+	dir := logging.DataDir()
+	if dir == "" {
+		return "", fmt.Errorf("failed to determine data directory")
+	}
+	return filepath.Join(dir, ledgerName), nil
+}
+
+// List returns the recorded snapshots, newest first.
+func List() ([]Entry, error) {
+	ledgerMu.Lock()
+	defer ledgerMu.Unlock()
+	entries, err := readLedger()
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Time.After(entries[j].Time) })
+	return entries, err
+}
+
+// Forget drops a deleted snapshot from the ledger.
+func Forget(e Entry) error {
+	ledgerMu.Lock()
+	defer ledgerMu.Unlock()
+	entries, err := readLedger()
+	if err != nil {
+		return err
+	}
+	path, err := ledgerPath()
+	if err != nil {
+		return err
+	}
+	var b strings.Builder
+	for _, old := range entries {
+		if old.Tool == e.Tool && old.ID == e.ID {
+			continue
+		}
+		line, err := json.Marshal(old)
+		if err != nil {
+			return err
+		}
+		b.Write(line)
+		b.WriteByte('\n')
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(b.String()), 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func appendLedger(e Entry) error {
+	ledgerMu.Lock()
+	defer ledgerMu.Unlock()
+	path, err := ledgerPath()
+	if err != nil {
+		return err
+	}
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(append(line, '\n'))
+	return err
+}
+
+// readLedger parses the ledger, skipping malformed lines. A missing ledger
+// is not an error.
+func readLedger() ([]Entry, error) {
+	path, err := ledgerPath()
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []Entry
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		var e Entry
+		if json.Unmarshal(sc.Bytes(), &e) != nil || e.Tool == "" || e.ID == "" {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	return entries, sc.Err()
+}
+
+// snapper takes single snapshots of the root config, numbered by snapper.
+type snapper struct{}
+
+func (snapper) Name() string { return "snapper" }
+
+func (snapper) create(desc string) string {
+	return fmt.Sprintf("sudo snapper -c root create --type single --cleanup-algorithm number --userdata mypctools=yes --description %s --print-number\n",
+		shellQuote(desc))
+}
+
+// snapperNumber matches the number printed by --print-number.
+var snapperNumber = regexp.MustCompile(`(?m)^\s*(\d+)\s*$`)
+
+func (snapper) parseID(output string) string {
+	return lastMatch(snapperNumber, output)
+}
+
+func (snapper) Delete(id string) *exec.Cmd {
+	return exec.Command("sudo", "snapper", "-c", "root", "delete", id)
+}
+
+// timeshift snapshots are named after their creation time.
+type timeshift struct{}
+
+func (timeshift) Name() string { return "timeshift" }
+
+func (timeshift) create(desc string) string {
+	return fmt.Sprintf("sudo timeshift --create --scripted --tags O --comments %s\n", shellQuote(desc))
+}
+
+// timeshiftTagged matches "Tagged snapshot '2025-01-02_15-04-05': ondemand".
+var timeshiftTagged = regexp.MustCompile(`Tagged snapshot '(\d{4}-\d{2}-\d{2}_\d{2}-\d{2}-\d{2})'`)
+
+func (timeshift) parseID(output string) string {
+	return lastMatch(timeshiftTagged, output)
+}
+
+func (timeshift) Delete(id string) *exec.Cmd {
+	return exec.Command("sudo", "timeshift", "--delete", "--scripted", "--snapshot", id)
+}
+
+// btrfs keeps read-only snapshots of / under btrfsDir.
+type btrfs struct{}
+
+func (btrfs) Name() string { return "btrfs" }
+
+func (btrfs) create(desc string) string {
+	path := filepath.Join(btrfsDir, "update-"+time.Now().Format("20060102-150405"))
+	return fmt.Sprintf("sudo mkdir -p %s && sudo btrfs subvolume snapshot -r / %s\n", btrfsDir, shellQuote(path))
+}
+
+// btrfsCreated matches "Create a readonly snapshot of '/' in '<path>'".
+var btrfsCreated = regexp.MustCompile(`snapshot of '/' in '([^']+)'`)
+
+func (btrfs) parseID(output string) string {
+	return lastMatch(btrfsCreated, output)
+}
+
+func (btrfs) Delete(id string) *exec.Cmd {
+	return exec.Command("sudo", "btrfs", "subvolume", "delete", id)
+}
+
+// lastMatch returns the first group of re's last match in s.
+func lastMatch(re *regexp.Regexp, s string) string {
+	m := re.FindAllStringSubmatch(s, -1)
+	if len(m) == 0 {
+		return ""
+	}
+	return m[len(m)-1][1]
+}
+
+func rootIsBtrfs() bool {
+	var st syscall.Statfs_t
+	return syscall.Statfs("/", &st) == nil && st.Type == btrfsMagic
+}
+
+func onPath(name string) bool {
+	_, err := exec.LookPath(name)
+	return err == nil
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// shellQuote single-quotes s for bash.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// RestoreHint tells the user how to roll the system back to e. Restoring
+// replaces the running root, so mypctools only suggests the command.
+func RestoreHint(e Entry) string {
+	switch e.Tool {
+	case "snapper":
+		return "sudo snapper -c root rollback " + e.ID + " && reboot"
+	case "timeshift":
+		return "sudo timeshift --restore --snapshot " + e.ID
+	case "btrfs":
+		return "boot a live system and replace the root subvolume with " + e.ID
+	}
+	return ""
+}
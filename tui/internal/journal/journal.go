@@ -0,0 +1,237 @@
+// Package journal streams a unit's systemd journal entries, parsed from
+// `journalctl -o json`.
+package journal
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// FileEnv names a file of journalctl JSON lines to read instead of running
+// journalctl, for testing the viewer without journald.
+const FileEnv = "MYPCTOOLS_JOURNAL_FILE"
+
+// backlog is how many past entries a stream starts with.
+const backlog = 2000
+
+// Syslog priorities (PRIORITY field).
+const (
+	PriorityErr     = 3
+	PriorityWarning = 4
+	PriorityNotice  = 5
+	PriorityInfo    = 6
+	PriorityDebug   = 7
+)
+
+// Entry is one journal record.
+type Entry struct {
+	Time     time.Time
+	Priority int
+	Ident    string // SYSLOG_IDENTIFIER, else the process name
+	PID      string
+	Message  string
+}
+
+// Parse decodes one line of `journalctl -o json` output.
+func Parse(line []byte) (Entry, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(line, &fields); err != nil {
+		return Entry{}, err
+	}
+	e := Entry{Priority: PriorityInfo, Message: field(fields, "MESSAGE")}
+	if us, err := strconv.ParseInt(field(fields, "__REALTIME_TIMESTAMP"), 10, 64); err == nil {
+		e.Time = time.UnixMicro(us)
+	}
+	if p, err := strconv.Atoi(field(fields, "PRIORITY")); err == nil {
+		e.Priority = p
+	}
+	e.Ident = field(fields, "SYSLOG_IDENTIFIER")
+	if e.Ident == "" {
+		e.Ident = field(fields, "_COMM")
+	}
+	e.PID = field(fields, "_PID")
+	return e, nil
+}
+
+// field returns a journal field as text. journald exports fields that are
+// not valid UTF-8 as arrays of byte values.
+func field(fields map[string]json.RawMessage, name string) string {
+	raw, ok := fields[name]
+	if !ok {
+		return ""
+	}
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return s
+	}
+	var b []byte
+	var ints []int
+	if json.Unmarshal(raw, &ints) == nil {
+		b = make([]byte, len(ints))
+		for i, n := range ints {
+			b[i] = byte(n)
+		}
+	}
+	return strings.ToValidUTF8(string(b), "�")
+}
+
+// String renders the entry like journalctl's short format.
+func (e Entry) String() string {
+	ts := e.Time.Format("Jan 02 15:04:05")
+	if e.Ident == "" {
+		return ts + " " + e.Message
+	}
+	src := e.Ident
+	if e.PID != "" {
+		src += "[" + e.PID + "]"
+	}
+	return fmt.Sprintf("%s %s: %s", ts, src, e.Message)
+}
+
+// Range limits which entries a stream starts from.
+type Range struct {
+	Label string
+	Live  bool // New entries can still arrive, so the stream follows
+	args  []string
+}
+
+// Ranges are the time ranges the log viewer cycles through.
+var Ranges = []Range{
+	{Label: "this boot", Live: true, args: []string{"--boot"}},
+	{Label: "last hour", Live: true, args: []string{"--since=-1h"}},
+	{Label: "today", Live: true, args: []string{"--since=today"}},
+	{Label: "previous boot", args: []string{"--boot=-1"}},
+	{Label: "all", Live: true},
+}
+
+var nextID atomic.Int64
+
+// Stream delivers a unit's entries as journalctl prints them.
+type Stream struct {
+	ID  int64
+	cmd *exec.Cmd
+	out chan Entry
+	err error // Why the stream ended, valid once out is closed
+}
+
+// Follow starts streaming the last entries of unit within r, then new ones
+// as they are logged when r is live. user selects the user journal.
+func Follow(unit string, user bool, r Range) (*Stream, error) {
+	s := &Stream{ID: nextID.Add(1), out: make(chan Entry, 256)}
+
+	if path := os.Getenv(FileEnv); path != "" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		go func() {
+			s.err = s.pump(f)
+			f.Close()
+			close(s.out)
+		}()
+		return s, nil
+	}
+
+	args := []string{"--output=json", "--no-pager", "--lines=" + strconv.Itoa(backlog)}
+	if user {
+		args = append(args, "--user", "--user-unit="+unit)
+	} else {
+		args = append(args, "--unit="+unit)
+	}
+	args = append(args, r.args...)
+	if r.Live {
+		args = append(args, "--follow")
+	}
+	s.cmd = exec.Command("journalctl", args...)
+	var stderr bytes.Buffer
+	s.cmd.Stderr = &stderr
+	stdout, err := s.cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.cmd.Start(); err != nil {
+		return nil, err
+	}
+	go func() {
+		err := s.pump(stdout)
+		if werr := s.cmd.Wait(); err == nil && werr != nil {
+			err = fmt.Errorf("journalctl: %s", firstLine(stderr.String(), werr.Error()))
+		}
+		if err == nil && stderr.Len() > 0 {
+			// e.g. "No journal files were opened due to insufficient permissions."
+			err = fmt.Errorf("journalctl: %s", firstLine(stderr.String(), ""))
+		}
+		s.err = err
+		close(s.out)
+	}()
+	return s, nil
+}
+
+// pump parses lines from r into out until EOF.
+func (s *Stream) pump(r io.Reader) error {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for sc.Scan() {
+		if len(bytes.TrimSpace(sc.Bytes())) == 0 {
+			continue
+		}
+		e, err := Parse(sc.Bytes())
+		if err != nil {
+			continue // Skip anything that isn't an entry
+		}
+		s.out <- e
+	}
+	return sc.Err()
+}
+
+// Next blocks for the next entry, then also takes whatever else is already
+// waiting (up to max entries). ok is false once the stream has ended.
+func (s *Stream) Next(max int) (entries []Entry, ok bool) {
+	e, ok := <-s.out
+	if !ok {
+		return nil, false
+	}
+	entries = append(entries, e)
+	for len(entries) < max {
+		select {
+		case e, ok := <-s.out:
+			if !ok {
+				return entries, true // Reported as ended on the next call
+			}
+			entries = append(entries, e)
+		default:
+			return entries, true
+		}
+	}
+	return entries, true
+}
+
+// Err reports why the stream ended (nil for a clean end of input).
+func (s *Stream) Err() error { return s.err }
+
+// Close stops journalctl and discards anything not yet read.
+func (s *Stream) Close() {
+	if s.cmd != nil && s.cmd.Process != nil {
+		s.cmd.Process.Kill()
+	}
+	go func() {
+		for range s.out {
+		}
+	}()
+}
+
+func firstLine(s, fallback string) string {
+	if line, _, _ := strings.Cut(strings.TrimSpace(s), "\n"); line != "" {
+		return line
+	}
+	return fallback
+}
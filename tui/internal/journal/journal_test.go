@@ -0,0 +1,106 @@
+package journal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want Entry
+	}{
+		{
+			name: "plain",
+			line: `{"__REALTIME_TIMESTAMP":"1760000000000000","PRIORITY":"3","SYSLOG_IDENTIFIER":"sshd","_PID":"812","MESSAGE":"boom"}`,
+			want: Entry{Time: time.UnixMicro(1760000000000000), Priority: PriorityErr, Ident: "sshd", PID: "812", Message: "boom"},
+		},
+		{
+			name: "message as bytes",
+			line: `{"PRIORITY":"4","SYSLOG_IDENTIFIER":"sshd","MESSAGE":[104,105]}`,
+			want: Entry{Priority: PriorityWarning, Ident: "sshd", Message: "hi"},
+		},
+		{
+			name: "message with invalid UTF-8",
+			line: `{"PRIORITY":"6","MESSAGE":[104,255]}`,
+			want: Entry{Priority: PriorityInfo, Message: "h�"},
+		},
+		{
+			name: "missing priority defaults to info",
+			line: `{"SYSLOG_IDENTIFIER":"systemd","MESSAGE":"Started."}`,
+			want: Entry{Priority: PriorityInfo, Ident: "systemd", Message: "Started."},
+		},
+		{
+			name: "identifier falls back to _COMM",
+			line: `{"_COMM":"bash","PRIORITY":"7","MESSAGE":"x"}`,
+			want: Entry{Priority: PriorityDebug, Ident: "bash", Message: "x"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse([]byte(tt.line))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.Time.Equal(tt.want.Time) {
+				t.Errorf("Time = %v, want %v", got.Time, tt.want.Time)
+			}
+			got.Time, tt.want.Time = time.Time{}, time.Time{}
+			if got != tt.want {
+				t.Errorf("Parse = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := Parse([]byte("not json")); err == nil {
+		t.Error("Parse accepted a line that isn't JSON")
+	}
+}
+
+func TestFollowFile(t *testing.T) {
+	path, err := filepath.Abs(filepath.Join("testdata", "sshd.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(FileEnv, path)
+
+	s, err := Follow("sshd", false, Ranges[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var entries []Entry
+	for {
+		batch, ok := s.Next(100)
+		if !ok {
+			break
+		}
+		entries = append(entries, batch...)
+	}
+	if err := s.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"Server listening on 0.0.0.0 port 22.",
+		"error: kex_exchange_identification: Connection closed by remote host",
+		"Invalid user �",
+		"Started OpenSSH Daemon.",
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("got %d entries, want %d (blank and malformed lines skipped)", len(entries), len(want))
+	}
+	for i, e := range entries {
+		if e.Message != want[i] {
+			t.Errorf("entry %d: Message = %q, want %q", i, e.Message, want[i])
+		}
+	}
+	if entries[2].Ident != "sshd" || entries[3].Priority != PriorityInfo {
+		t.Errorf("fallbacks not applied: %+v, %+v", entries[2], entries[3])
+	}
+}
@@ -33,6 +33,12 @@ func ensureLogDir() string {
 	return logDirPath
 }
 
+// DataDir returns ~/.local/share/mypctools, creating it if needed.
+// Returns "" if the home directory cannot be determined.
+func DataDir() string {
+	return ensureLogDir()
+}
+
 // LogAction appends a timestamped line to ~/.local/share/mypctools/mypctools.log.
 func LogAction(action string) error {
 	logMu.Lock()
@@ -0,0 +1,110 @@
+package pkgmgr
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// Apt is the Debian/Ubuntu package manager.
+type Apt struct {
+	run Runner
+}
+
+// NewApt returns an apt backend that queries through r.
+func NewApt(r Runner) *Apt { return &Apt{run: r} }
+
+func (a *Apt) Name() string { return "apt" }
+
+func (a *Apt) Install(pkgs ...string) *exec.Cmd {
+	return sudo("apt", append([]string{"install", "-y"}, pkgs...)...)
+}
+
+func (a *Apt) Remove(pkgs ...string) *exec.Cmd {
+	return sudo("apt", append([]string{"remove", "-y"}, pkgs...)...)
+}
+
+func (a *Apt) IsInstalled(pkg string) (bool, error) {
+	out, err := a.run.Output("dpkg-query", "-W", "-f=${Status}", pkg)
+	if exitCode(err) == 1 {
+		return false, nil // Unknown package
+	}
+	if err != nil {
+		return false, err
+	}
+	return strings.HasSuffix(strings.TrimSpace(string(out)), " installed"), nil
+}
+
+// ListUpgradable parses `apt list --upgradable` against the current package
+// lists; run Update first for fresh results.
+func (a *Apt) ListUpgradable() ([]Upgrade, error) {
+	out, err := a.run.Output("apt", "list", "--upgradable")
+	if err != nil {
+		return nil, err
+	}
+	var ups []Upgrade
+	for _, l := range lines(out) {
+		// name/suite version arch [upgradable from: old]
+		name, rest, ok := strings.Cut(l, "/")
+		if !ok {
+			continue // "Listing..." header
+		}
+		f := strings.Fields(rest)
+		if len(f) < 2 {
+			continue
+		}
+		u := Upgrade{Name: name, Available: f[1]}
+		if _, from, ok := strings.Cut(rest, "upgradable from: "); ok {
+			u.Current = strings.TrimSuffix(from, "]")
+		}
+		ups = append(ups, u)
+	}
+	return ups, nil
+}
+
+func (a *Apt) Refresh() *exec.Cmd { return sudo("apt", "update") }
+
+// Update holds excluded packages for the duration of the upgrade.
+func (a *Apt) Update(exclude ...string) *exec.Cmd {
+	upgrade := sudo("apt", "upgrade", "-y")
+	if len(exclude) > 0 {
+		upgrade = holdDuring(
+			sudo("apt-mark", append([]string{"hold"}, exclude...)...),
+			upgrade,
+			sudo("apt-mark", append([]string{"unhold"}, exclude...)...),
+		)
+	}
+	return Chain(a.Refresh(), upgrade)
+}
+
+func (a *Apt) Clean() *exec.Cmd {
+	return Chain(sudo("apt", "autoclean"), sudo("apt", "clean"))
+}
+
+func (a *Apt) CacheDirs() []string { return []string{"/var/cache/apt/archives"} }
+
+// InstalledSize sums dpkg's Installed-Size fields, which are in KiB.
+func (a *Apt) InstalledSize(pkgs ...string) (int64, error) {
+	if len(pkgs) == 0 {
+		return 0, nil
+	}
+	out, err := a.run.Output("dpkg-query", append([]string{"-W", "-f=${Installed-Size}\n"}, pkgs...)...)
+	if err != nil {
+		return 0, err
+	}
+	return sumLines(out, 1024)
+}
+
+// Orphans lists what `apt autoremove` would remove, from a simulated run.
+func (a *Apt) Orphans() ([]string, error) {
+	out, err := a.run.Output("apt-get", "-s", "autoremove")
+	if err != nil {
+		return nil, err
+	}
+	var pkgs []string
+	for _, l := range lines(out) {
+		if rest, ok := strings.CutPrefix(l, "Remv "); ok {
+			pkgs = append(pkgs, strings.Fields(rest)[0])
+		}
+	}
+	return pkgs, nil
+}
@@ -0,0 +1,45 @@
+package pkgmgr
+
+import "path"
+
+// criticalPatterns are glob patterns for packages whose upgrade deserves a
+// second look before it runs: a bad kernel, libc or driver can leave the
+// machine unbootable or without a display. First match wins.
+var criticalPatterns = []struct {
+	pattern string
+	reason  string
+}{
+	{"linux-firmware*", "firmware"},
+	{"linux", "kernel"},
+	{"linux-*", "kernel"},
+	{"kernel", "kernel"},
+	{"kernel-*", "kernel"},
+	{"glibc", "glibc"},
+	{"glibc-*", "glibc"},
+	{"libc6", "glibc"},
+	{"libc6-*", "glibc"},
+	{"libc-bin", "glibc"},
+	{"nvidia*", "driver"},
+	{"libnvidia*", "driver"},
+	{"mesa*", "driver"},
+	{"lib32-mesa*", "driver"},
+	{"lib32-nvidia*", "driver"},
+	{"vulkan-radeon", "driver"},
+	{"vulkan-intel", "driver"},
+	{"xf86-video-*", "driver"},
+	{"xserver-xorg-video-*", "driver"},
+	{"*-ucode", "microcode"},
+	{"*-microcode", "microcode"},
+	{"ucode-*", "microcode"},
+}
+
+// Critical reports whether upgrading pkg warrants extra care, and why
+// ("kernel", "glibc", "driver", ...).
+func Critical(pkg string) (string, bool) {
+	for _, c := range criticalPatterns {
+		if ok, _ := path.Match(c.pattern, pkg); ok {
+			return c.reason, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,116 @@
+package pkgmgr
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// Dnf is the Fedora/RHEL package manager.
+type Dnf struct {
+	run Runner
+}
+
+// NewDnf returns a dnf backend that queries through r.
+func NewDnf(r Runner) *Dnf { return &Dnf{run: r} }
+
+func (d *Dnf) Name() string { return "dnf" }
+
+func (d *Dnf) Install(pkgs ...string) *exec.Cmd {
+	return sudo("dnf", append([]string{"install", "-y"}, pkgs...)...)
+}
+
+func (d *Dnf) Remove(pkgs ...string) *exec.Cmd {
+	return sudo("dnf", append([]string{"remove", "-y"}, pkgs...)...)
+}
+
+func (d *Dnf) IsInstalled(pkg string) (bool, error) {
+	return rpmInstalled(d.run, pkg)
+}
+
+// ListUpgradable parses `dnf check-update`, which exits 100 when upgrades
+// are available. Names too long for their column are printed on a line of
+// their own, followed by the version and repo.
+func (d *Dnf) ListUpgradable() ([]Upgrade, error) {
+	out, err := d.run.Output("dnf", "check-update", "-q")
+	if err != nil && exitCode(err) != 100 {
+		return nil, err
+	}
+	var ups []Upgrade
+	var wrapped string // A name too long for its column, alone on its line
+	for _, l := range lines(out) {
+		if strings.HasPrefix(strings.ToLower(l), "obsoleting packages") {
+			break // Repeats upgrades, then lists installed packages they replace
+		}
+		// name.arch version repo
+		f := strings.Fields(l)
+		switch {
+		case len(f) == 1:
+			wrapped = f[0]
+			continue
+		case len(f) == 2 && wrapped != "":
+			f = append([]string{wrapped}, f...)
+		}
+		wrapped = ""
+		if len(f) != 3 {
+			continue
+		}
+		name := f[0]
+		if i := strings.LastIndex(name, "."); i > 0 {
+			name = name[:i]
+		}
+		ups = append(ups, Upgrade{Name: name, Available: f[1]})
+	}
+	return ups, nil
+}
+
+// Refresh is not needed: check-update refreshes expired metadata itself.
+func (d *Dnf) Refresh() *exec.Cmd { return nil }
+
+func (d *Dnf) Update(exclude ...string) *exec.Cmd {
+	args := []string{"upgrade", "--refresh", "-y"}
+	if len(exclude) > 0 {
+		args = append(args, "--exclude="+strings.Join(exclude, ","))
+	}
+	return sudo("dnf", args...)
+}
+
+func (d *Dnf) Clean() *exec.Cmd {
+	return sudo("dnf", "clean", "all")
+}
+
+// CacheDirs covers both dnf4 and dnf5 (libdnf5) cache locations.
+func (d *Dnf) CacheDirs() []string { return []string{"/var/cache/dnf", "/var/cache/libdnf5"} }
+
+func (d *Dnf) InstalledSize(pkgs ...string) (int64, error) {
+	return rpmSize(d.run, pkgs)
+}
+
+// Orphans lists packages `dnf autoremove` would remove.
+func (d *Dnf) Orphans() ([]string, error) {
+	out, err := d.run.Output("dnf", "repoquery", "--unneeded", "-q", "--qf", "%{name}\n")
+	if err != nil {
+		return nil, err
+	}
+	return lines(out), nil
+}
+
+// rpmSize sums the installed sizes rpm records, in bytes.
+func rpmSize(r Runner, pkgs []string) (int64, error) {
+	if len(pkgs) == 0 {
+		return 0, nil
+	}
+	out, err := r.Output("rpm", append([]string{"-q", "--qf", "%{SIZE}\n"}, pkgs...)...)
+	if err != nil {
+		return 0, err
+	}
+	return sumLines(out, 1)
+}
+
+// rpmInstalled asks the rpm database, shared by dnf and zypper.
+func rpmInstalled(r Runner, pkg string) (bool, error) {
+	_, err := r.Output("rpm", "-q", pkg)
+	if exitCode(err) == 1 {
+		return false, nil
+	}
+	return err == nil, err
+}
@@ -0,0 +1,46 @@
+package pkgmgr
+
+import "os/exec"
+
+// Extra is an update source outside the native package manager, run as an
+// extra stage of a full system update.
+type Extra struct {
+	Name    string // Shown in stage summaries
+	Binary  string // Must be on PATH for the stage to run
+	Command func() *exec.Cmd
+}
+
+// Available reports whether the extra's tool is installed.
+func (e Extra) Available() bool {
+	_, err := exec.LookPath(e.Binary)
+	return err == nil
+}
+
+// Extras are the update sources checked after the system packages.
+var Extras = []Extra{
+	{Name: "Flatpak", Binary: "flatpak", Command: flatpakUpdate},
+	{Name: "Snap", Binary: "snap", Command: snapRefresh},
+	{Name: "Firmware", Binary: "fwupdmgr", Command: firmwareUpdate},
+}
+
+// flatpakUpdate updates per-user apps, then system-wide ones (which need
+// root when no polkit agent is running, as in a bare terminal).
+func flatpakUpdate() *exec.Cmd {
+	return Chain(
+		exec.Command("flatpak", "update", "--user", "-y", "--noninteractive"),
+		sudo("flatpak", "update", "--system", "-y", "--noninteractive"),
+	)
+}
+
+func snapRefresh() *exec.Cmd {
+	return sudo("snap", "refresh")
+}
+
+// firmwareUpdate refreshes LVFS metadata and applies firmware updates.
+// fwupdmgr exits 2 when there is nothing to do, which is not a failure.
+func firmwareUpdate() *exec.Cmd {
+	return exec.Command("bash", "-c",
+		"sudo fwupdmgr refresh --force >/dev/null\n"+
+			"sudo fwupdmgr update -y --no-reboot-check\n"+
+			"rc=$?\n[ $rc -eq 2 ] && exit 0\nexit $rc\n")
+}
@@ -0,0 +1,202 @@
+package pkgmgr
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Pacman is the Arch package manager.
+type Pacman struct {
+	run Runner
+}
+
+// NewPacman returns a pacman backend that queries through r.
+func NewPacman(r Runner) *Pacman { return &Pacman{run: r} }
+
+func (p *Pacman) Name() string { return "pacman" }
+
+func (p *Pacman) Install(pkgs ...string) *exec.Cmd {
+	return sudo("pacman", append([]string{"-S", "--noconfirm", "--needed"}, pkgs...)...)
+}
+
+func (p *Pacman) Remove(pkgs ...string) *exec.Cmd {
+	return sudo("pacman", append([]string{"-Rns", "--noconfirm"}, pkgs...)...)
+}
+
+func (p *Pacman) IsInstalled(pkg string) (bool, error) {
+	_, err := p.run.Output("pacman", "-Q", pkg)
+	if exitCode(err) == 1 {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// ListUpgradable prefers checkupdates (pacman-contrib), which syncs a
+// temporary database instead of the system one; without it the local
+// database may be stale.
+func (p *Pacman) ListUpgradable() ([]Upgrade, error) {
+	out, err := p.run.Output("checkupdates")
+	switch {
+	case errors.Is(err, exec.ErrNotFound):
+		out, err = p.run.Output("pacman", "-Qu")
+		if exitCode(err) == 1 {
+			return nil, nil // Nothing to upgrade
+		}
+	case exitCode(err) == 2:
+		return nil, nil // Nothing to upgrade
+	}
+	if err != nil {
+		return nil, err
+	}
+	return parseArrowList(out), nil
+}
+
+// Refresh is not needed: checkupdates syncs its own temporary database.
+// (Without pacman-contrib the list comes from the local database, and a
+// plain -Sy would risk a partial upgrade.)
+func (p *Pacman) Refresh() *exec.Cmd { return nil }
+
+func (p *Pacman) Update(exclude ...string) *exec.Cmd {
+	return sudo("pacman", withIgnore([]string{"-Syu", "--noconfirm"}, exclude)...)
+}
+
+// withIgnore appends pacman's --ignore flag for exclude.
+func withIgnore(args, exclude []string) []string {
+	if len(exclude) > 0 {
+		args = append(args, "--ignore", strings.Join(exclude, ","))
+	}
+	return args
+}
+
+// Clean keeps the last two versions of each package when paccache is
+// available, otherwise drops cached packages that are no longer installed.
+func (p *Pacman) Clean() *exec.Cmd {
+	if _, err := exec.LookPath("paccache"); err == nil {
+		return sudo("paccache", "-rk2")
+	}
+	return sudo("pacman", "-Sc", "--noconfirm")
+}
+
+func (p *Pacman) CacheDirs() []string { return []string{"/var/cache/pacman/pkg"} }
+
+// InstalledSize sums the "Installed Size" fields of `pacman -Qi`.
+func (p *Pacman) InstalledSize(pkgs ...string) (int64, error) {
+	if len(pkgs) == 0 {
+		return 0, nil
+	}
+	out, err := p.run.Output("env", append([]string{"LC_ALL=C", "pacman", "-Qi"}, pkgs...)...)
+	if err != nil {
+		return 0, err
+	}
+	var total int64
+	for _, l := range lines(out) {
+		key, val, ok := strings.Cut(l, ":")
+		if !ok || strings.TrimSpace(key) != "Installed Size" {
+			continue
+		}
+		n, err := parseUnitSize(strings.TrimSpace(val))
+		if err != nil {
+			return 0, err
+		}
+		total += n
+	}
+	return total, nil
+}
+
+// pacmanUnits are the binary units pacman prints sizes in.
+var pacmanUnits = map[string]float64{"B": 1, "KiB": 1 << 10, "MiB": 1 << 20, "GiB": 1 << 30, "TiB": 1 << 40}
+
+// parseUnitSize parses sizes like "1.50 MiB".
+func parseUnitSize(s string) (int64, error) {
+	f := strings.Fields(s)
+	if len(f) == 2 {
+		if unit, ok := pacmanUnits[f[1]]; ok {
+			if n, err := strconv.ParseFloat(f[0], 64); err == nil {
+				return int64(n * unit), nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("unexpected size %q", s)
+}
+
+func (p *Pacman) Orphans() ([]string, error) {
+	out, err := p.run.Output("pacman", "-Qtdq")
+	if exitCode(err) == 1 {
+		return nil, nil // No orphans
+	}
+	if err != nil {
+		return nil, err
+	}
+	return lines(out), nil
+}
+
+// AUR is an AUR helper (paru, yay) layered on pacman. Helpers call sudo
+// themselves and must not be run as root.
+type AUR struct {
+	*Pacman
+	helper string
+}
+
+// NewAUR returns a backend for the given AUR helper that queries through r.
+func NewAUR(helper string, r Runner) *AUR {
+	return &AUR{Pacman: NewPacman(r), helper: helper}
+}
+
+func (a *AUR) Name() string { return a.helper }
+
+func (a *AUR) Install(pkgs ...string) *exec.Cmd {
+	return exec.Command(a.helper, append([]string{"-S", "--needed", "--noconfirm"}, pkgs...)...)
+}
+
+// ListUpgradable adds AUR packages to the repo upgrades.
+func (a *AUR) ListUpgradable() ([]Upgrade, error) {
+	ups, err := a.Pacman.ListUpgradable()
+	if err != nil {
+		return nil, err
+	}
+	out, err := a.run.Output(a.helper, "-Qua")
+	if exitCode(err) == 1 {
+		return ups, nil // No AUR upgrades
+	}
+	if err != nil {
+		return nil, err
+	}
+	return append(ups, parseArrowList(out)...), nil
+}
+
+func (a *AUR) Update(exclude ...string) *exec.Cmd {
+	return exec.Command(a.helper, withIgnore([]string{"-Syu", "--noconfirm"}, exclude)...)
+}
+
+// Clean also clears the helper's build cache.
+func (a *AUR) Clean() *exec.Cmd {
+	return Chain(a.Pacman.Clean(), exec.Command(a.helper, "-Sc", "--noconfirm"))
+}
+
+// CacheDirs adds the helper's build cache.
+func (a *AUR) CacheDirs() []string {
+	dirs := a.Pacman.CacheDirs()
+	if home, err := os.UserHomeDir(); err == nil {
+		dirs = append(dirs, filepath.Join(home, ".cache", a.helper))
+	}
+	return dirs
+}
+
+// parseArrowList parses "name old -> new" lines (checkupdates, pacman -Qu,
+// paru/yay -Qua). Trailing markers such as "[ignored]" are dropped.
+func parseArrowList(out []byte) []Upgrade {
+	var ups []Upgrade
+	for _, l := range lines(out) {
+		f := strings.Fields(l)
+		if len(f) < 4 || f[2] != "->" {
+			continue
+		}
+		ups = append(ups, Upgrade{Name: f[0], Current: f[1], Available: f[3]})
+	}
+	return ups
+}
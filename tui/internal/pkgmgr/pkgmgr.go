@@ -0,0 +1,167 @@
+// Package pkgmgr wraps the system package managers behind one interface.
+//
+// Methods that change the system return an *exec.Cmd so screens can run them
+// interactively (sudo prompts, progress output). Read-only queries run through
+// a Runner, which tests can replace with a fake backend.
+package pkgmgr
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/reisset/mypctools/tui/internal/cmd"
+)
+
+// PackageManager is the common surface of pacman, apt, dnf, zypper and the
+// AUR helpers.
+type PackageManager interface {
+	// Name is the command users know the manager by (e.g. "pacman", "paru").
+	Name() string
+	// Install returns a command that installs pkgs.
+	Install(pkgs ...string) *exec.Cmd
+	// Remove returns a command that removes pkgs.
+	Remove(pkgs ...string) *exec.Cmd
+	// IsInstalled reports whether pkg is installed.
+	IsInstalled(pkg string) (bool, error)
+	// Refresh returns a command that syncs package lists so ListUpgradable
+	// is current, or nil when ListUpgradable fetches fresh data itself.
+	Refresh() *exec.Cmd
+	// ListUpgradable returns packages with a newer version available.
+	ListUpgradable() ([]Upgrade, error)
+	// Update returns a command that refreshes the package database and
+	// upgrades the whole system, leaving the packages in exclude alone.
+	Update(exclude ...string) *exec.Cmd
+	// Clean returns a command that clears the package cache.
+	Clean() *exec.Cmd
+	// CacheDirs lists the directories Clean prunes, for measuring.
+	CacheDirs() []string
+	// InstalledSize returns the disk space pkgs take up, in bytes.
+	InstalledSize(pkgs ...string) (int64, error)
+	// Orphans returns packages installed as dependencies that nothing needs.
+	Orphans() ([]string, error)
+}
+
+// Upgrade is one pending package upgrade.
+type Upgrade struct {
+	Name      string
+	Current   string // Installed version ("" if the manager doesn't report it)
+	Available string
+}
+
+// Runner runs read-only package queries.
+type Runner interface {
+	Output(name string, args ...string) ([]byte, error)
+}
+
+type execRunner struct{}
+
+func (execRunner) Output(name string, args ...string) ([]byte, error) {
+	return exec.Command(name, args...).Output()
+}
+
+// aurHelpers are tried in order on Arch; the first on PATH wins.
+var aurHelpers = []string{"paru", "yay"}
+
+// For returns the package manager for a detected distro, or nil when the
+// distro is unsupported. On Arch an installed AUR helper is preferred.
+func For(info cmd.DistroInfo) PackageManager {
+	r := execRunner{}
+	switch info.Type {
+	case cmd.DistroArch:
+		for _, h := range aurHelpers {
+			if _, err := exec.LookPath(h); err == nil {
+				return NewAUR(h, r)
+			}
+		}
+		return NewPacman(r)
+	case cmd.DistroDebian:
+		return NewApt(r)
+	case cmd.DistroFedora:
+		return NewDnf(r)
+	case cmd.DistroSUSE:
+		return NewZypper(isRolling(info.ID), r)
+	}
+	return nil
+}
+
+// Chain joins commands into one that runs them in order, stopping at the
+// first failure, so a screen can run several steps as a single job.
+func Chain(cmds ...*exec.Cmd) *exec.Cmd {
+	if len(cmds) == 1 {
+		return cmds[0]
+	}
+	steps := make([]string, len(cmds))
+	for i, c := range cmds {
+		steps[i] = quoteArgs(c)
+	}
+	return exec.Command("bash", "-c", strings.Join(steps, " && "))
+}
+
+// holdDuring runs upgrade with pkgs locked by the hold/unhold commands,
+// releasing the locks even when the upgrade fails. For managers without an
+// exclude flag.
+func holdDuring(hold, upgrade, unhold *exec.Cmd) *exec.Cmd {
+	script := fmt.Sprintf("%s >/dev/null || exit 1\n%s\nrc=$?\n%s >/dev/null\nexit $rc\n",
+		quoteArgs(hold), quoteArgs(upgrade), quoteArgs(unhold))
+	return exec.Command("bash", "-c", script)
+}
+
+// quoteArgs renders a command as a bash command line.
+func quoteArgs(c *exec.Cmd) string {
+	args := make([]string, len(c.Args))
+	for i, a := range c.Args {
+		args[i] = shellQuote(a)
+	}
+	return strings.Join(args, " ")
+}
+
+// shellQuote quotes s for bash unless it is made of safe characters only.
+func shellQuote(s string) string {
+	if s != "" && strings.IndexFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_./=:+@", r))
+	}) < 0 {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// exitCode returns the exit status carried by err, or -1.
+func exitCode(err error) int {
+	var coded interface{ ExitCode() int }
+	if errors.As(err, &coded) {
+		return coded.ExitCode()
+	}
+	return -1
+}
+
+// lines splits output into non-empty trimmed lines.
+func lines(out []byte) []string {
+	var ls []string
+	for _, l := range strings.Split(string(out), "\n") {
+		if l = strings.TrimSpace(l); l != "" {
+			ls = append(ls, l)
+		}
+	}
+	return ls
+}
+
+// sumLines adds up one integer per line of out, each multiplied by unit.
+func sumLines(out []byte, unit int64) (int64, error) {
+	var total int64
+	for _, l := range lines(out) {
+		n, err := strconv.ParseInt(l, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("unexpected size %q", l)
+		}
+		total += n * unit
+	}
+	return total, nil
+}
+
+// sudo prefixes a command with sudo.
+func sudo(name string, args ...string) *exec.Cmd {
+	return exec.Command("sudo", append([]string{name}, args...)...)
+}
@@ -0,0 +1,309 @@
+package pkgmgr
+
+import (
+	"fmt"
+	"os/exec"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// exitError is a command that ran and exited with a status.
+type exitError int
+
+func (e exitError) Error() string { return fmt.Sprintf("exit status %d", int(e)) }
+func (e exitError) ExitCode() int { return int(e) }
+
+// result is what a fake command prints and how it exits.
+type result struct {
+	out  string
+	code int
+}
+
+// fakeRunner answers queries from canned results keyed by the command line.
+// Commands it doesn't know are not installed.
+type fakeRunner map[string]result
+
+func (f fakeRunner) Output(name string, args ...string) ([]byte, error) {
+	r, ok := f[strings.Join(append([]string{name}, args...), " ")]
+	if !ok {
+		return nil, &exec.Error{Name: name, Err: exec.ErrNotFound}
+	}
+	if r.code != 0 {
+		return []byte(r.out), exitError(r.code)
+	}
+	return []byte(r.out), nil
+}
+
+func TestListUpgradable(t *testing.T) {
+	tests := []struct {
+		name string
+		pm   func(Runner) PackageManager
+		run  fakeRunner
+		want []Upgrade
+	}{
+		{
+			name: "checkupdates",
+			pm:   func(r Runner) PackageManager { return NewPacman(r) },
+			run: fakeRunner{"checkupdates": {out: `linux 6.6.1.arch1-1 -> 6.6.2.arch1-1
+mesa 1:23.2.1-2 -> 1:23.3.0-1
+`}},
+			want: []Upgrade{
+				{Name: "linux", Current: "6.6.1.arch1-1", Available: "6.6.2.arch1-1"},
+				{Name: "mesa", Current: "1:23.2.1-2", Available: "1:23.3.0-1"},
+			},
+		},
+		{
+			name: "checkupdates, nothing to upgrade",
+			pm:   func(r Runner) PackageManager { return NewPacman(r) },
+			run:  fakeRunner{"checkupdates": {code: 2}},
+		},
+		{
+			name: "pacman -Qu without pacman-contrib",
+			pm:   func(r Runner) PackageManager { return NewPacman(r) },
+			run: fakeRunner{"pacman -Qu": {out: `firefox 119.0-1 -> 120.0-1
+linux-firmware 20231030-1 -> 20231110-1 [ignored]
+`}},
+			want: []Upgrade{
+				{Name: "firefox", Current: "119.0-1", Available: "120.0-1"},
+				{Name: "linux-firmware", Current: "20231030-1", Available: "20231110-1"},
+			},
+		},
+		{
+			name: "pacman -Qu, nothing to upgrade",
+			pm:   func(r Runner) PackageManager { return NewPacman(r) },
+			run:  fakeRunner{"pacman -Qu": {code: 1}},
+		},
+		{
+			name: "paru adds AUR upgrades",
+			pm:   func(r Runner) PackageManager { return NewAUR("paru", r) },
+			run: fakeRunner{
+				"checkupdates": {out: "linux 6.6.1.arch1-1 -> 6.6.2.arch1-1\n"},
+				"paru -Qua":    {out: ":: Looking for devel upgrades...\nvisual-studio-code-bin 1.84.1-1 -> 1.84.2-1\n"},
+			},
+			want: []Upgrade{
+				{Name: "linux", Current: "6.6.1.arch1-1", Available: "6.6.2.arch1-1"},
+				{Name: "visual-studio-code-bin", Current: "1.84.1-1", Available: "1.84.2-1"},
+			},
+		},
+		{
+			name: "paru, no AUR upgrades",
+			pm:   func(r Runner) PackageManager { return NewAUR("paru", r) },
+			run: fakeRunner{
+				"checkupdates": {out: "linux 6.6.1.arch1-1 -> 6.6.2.arch1-1\n"},
+				"paru -Qua":    {code: 1},
+			},
+			want: []Upgrade{{Name: "linux", Current: "6.6.1.arch1-1", Available: "6.6.2.arch1-1"}},
+		},
+		{
+			name: "apt list --upgradable",
+			pm:   func(r Runner) PackageManager { return NewApt(r) },
+			run: fakeRunner{"apt list --upgradable": {out: `Listing...
+firefox/jammy-updates 120.0+build2-0ubuntu0.22.04.1 amd64 [upgradable from: 119.0+build2-0ubuntu0.22.04.1]
+libc6/jammy-security,jammy-updates 2.35-0ubuntu3.5 amd64 [upgradable from: 2.35-0ubuntu3.4]
+`}},
+			want: []Upgrade{
+				{Name: "firefox", Current: "119.0+build2-0ubuntu0.22.04.1", Available: "120.0+build2-0ubuntu0.22.04.1"},
+				{Name: "libc6", Current: "2.35-0ubuntu3.4", Available: "2.35-0ubuntu3.5"},
+			},
+		},
+		{
+			name: "dnf check-update",
+			pm:   func(r Runner) PackageManager { return NewDnf(r) },
+			run: fakeRunner{"dnf check-update -q": {code: 100, out: `
+firefox.x86_64                          120.0-1.fc39             updates
+kernel-core.x86_64                      6.6.2-200.fc39           updates
+python3-setuptools-wheel-and-friends.noarch
+                                        68.2.2-1.fc39            updates
+Obsoleting Packages
+grub2-tools.x86_64                      1:2.06-100.fc39          updates
+    grub2-tools-extra.x86_64            1:2.06-95.fc39           @updates
+`}},
+			want: []Upgrade{
+				{Name: "firefox", Available: "120.0-1.fc39"},
+				{Name: "kernel-core", Available: "6.6.2-200.fc39"},
+				{Name: "python3-setuptools-wheel-and-friends", Available: "68.2.2-1.fc39"},
+			},
+		},
+		{
+			name: "dnf check-update, nothing to upgrade",
+			pm:   func(r Runner) PackageManager { return NewDnf(r) },
+			run:  fakeRunner{"dnf check-update -q": {}},
+		},
+		{
+			name: "zypper list-updates",
+			pm:   func(r Runner) PackageManager { return NewZypper(false, r) },
+			run: fakeRunner{"zypper --quiet list-updates": {out: `S | Repository   | Name    | Current Version | Available Version | Arch
+--+--------------+---------+-----------------+-------------------+-------
+v | repo-update  | curl    | 8.0.1-150400.5  | 8.0.1-150400.6    | x86_64
+v | repo-update  | openssl | 3.1.4-1.1       | 3.1.4-2.1         | x86_64
+`}},
+			want: []Upgrade{
+				{Name: "curl", Current: "8.0.1-150400.5", Available: "8.0.1-150400.6"},
+				{Name: "openssl", Current: "3.1.4-1.1", Available: "3.1.4-2.1"},
+			},
+		},
+		{
+			name: "zypper, nothing to upgrade",
+			pm:   func(r Runner) PackageManager { return NewZypper(false, r) },
+			run:  fakeRunner{"zypper --quiet list-updates": {out: "No updates found.\n"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.pm(tt.run).ListUpgradable()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListUpgradableError(t *testing.T) {
+	run := fakeRunner{"dnf check-update -q": {code: 1, out: "Error: Failed to download metadata"}}
+	if _, err := NewDnf(run).ListUpgradable(); err == nil {
+		t.Error("dnf failure was not reported")
+	}
+}
+
+func TestOrphans(t *testing.T) {
+	tests := []struct {
+		name string
+		pm   func(Runner) PackageManager
+		run  fakeRunner
+		want []string
+	}{
+		{
+			name: "pacman",
+			pm:   func(r Runner) PackageManager { return NewPacman(r) },
+			run:  fakeRunner{"pacman -Qtdq": {out: "gtk-doc\npython-sphinx\n"}},
+			want: []string{"gtk-doc", "python-sphinx"},
+		},
+		{
+			name: "pacman, none",
+			pm:   func(r Runner) PackageManager { return NewPacman(r) },
+			run:  fakeRunner{"pacman -Qtdq": {code: 1}},
+		},
+		{
+			name: "apt",
+			pm:   func(r Runner) PackageManager { return NewApt(r) },
+			run: fakeRunner{"apt-get -s autoremove": {out: `Reading package lists...
+The following packages will be REMOVED:
+  libfoo1 linux-image-6.2.0-26-generic
+0 upgraded, 0 newly installed, 2 to remove and 0 not upgraded.
+Remv libfoo1 [1.2-3]
+Remv linux-image-6.2.0-26-generic [6.2.0-26.26~22.04.1]
+`}},
+			want: []string{"libfoo1", "linux-image-6.2.0-26-generic"},
+		},
+		{
+			name: "dnf",
+			pm:   func(r Runner) PackageManager { return NewDnf(r) },
+			run:  fakeRunner{"dnf repoquery --unneeded -q --qf %{name}\n": {out: "libfoo\nperl-Bar\n"}},
+			want: []string{"libfoo", "perl-Bar"},
+		},
+		{
+			name: "zypper lists each package once",
+			pm:   func(r Runner) PackageManager { return NewZypper(true, r) },
+			run: fakeRunner{"zypper --quiet packages --unneeded": {out: `S | Repository | Name   | Version | Arch
+--+------------+--------+---------+-------
+i | repo-oss   | libfoo | 1.2-1.1 | x86_64
+i | repo-oss   | libfoo | 1.2-1.1 | i586
+i | repo-oss   | bar    | 0.9-2.3 | noarch
+`}},
+			want: []string{"libfoo", "bar"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.pm(tt.run).Orphans()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInstalledSize(t *testing.T) {
+	tests := []struct {
+		name    string
+		pm      func(Runner) PackageManager
+		run     fakeRunner
+		want    int64
+		wantErr bool
+	}{
+		{
+			name: "pacman",
+			pm:   func(r Runner) PackageManager { return NewPacman(r) },
+			run: fakeRunner{"env LC_ALL=C pacman -Qi gtk-doc python-sphinx": {out: `Name            : gtk-doc
+Installed Size  : 1.50 MiB
+Packager        : Someone <someone@archlinux.org>
+
+Name            : python-sphinx
+Installed Size  : 512.00 KiB
+`}},
+			want: 3<<19 + 512<<10,
+		},
+		{
+			name:    "pacman, unknown unit",
+			pm:      func(r Runner) PackageManager { return NewPacman(r) },
+			run:     fakeRunner{"env LC_ALL=C pacman -Qi gtk-doc python-sphinx": {out: "Installed Size  : 1,50 MiB\n"}},
+			wantErr: true,
+		},
+		{
+			name: "apt, in KiB",
+			pm:   func(r Runner) PackageManager { return NewApt(r) },
+			run:  fakeRunner{"dpkg-query -W -f=${Installed-Size}\n gtk-doc python-sphinx": {out: "100\n28\n"}},
+			want: 128 << 10,
+		},
+		{
+			name: "rpm, in bytes",
+			pm:   func(r Runner) PackageManager { return NewDnf(r) },
+			run:  fakeRunner{"rpm -q --qf %{SIZE}\n gtk-doc python-sphinx": {out: "1000\n234\n"}},
+			want: 1234,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.pm(tt.run).InstalledSize("gtk-doc", "python-sphinx")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, want error %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsInstalled(t *testing.T) {
+	tests := []struct {
+		name string
+		pm   func(Runner) PackageManager
+		run  fakeRunner
+		want bool
+	}{
+		{"pacman", func(r Runner) PackageManager { return NewPacman(r) }, fakeRunner{"pacman -Q git": {out: "git 2.43.0-1\n"}}, true},
+		{"pacman, missing", func(r Runner) PackageManager { return NewPacman(r) }, fakeRunner{"pacman -Q git": {code: 1}}, false},
+		{"apt", func(r Runner) PackageManager { return NewApt(r) }, fakeRunner{"dpkg-query -W -f=${Status} git": {out: "install ok installed"}}, true},
+		{"apt, removed", func(r Runner) PackageManager { return NewApt(r) }, fakeRunner{"dpkg-query -W -f=${Status} git": {out: "deinstall ok config-files"}}, false},
+		{"rpm, missing", func(r Runner) PackageManager { return NewZypper(false, r) }, fakeRunner{"rpm -q git": {code: 1}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.pm(tt.run).IsInstalled("git")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
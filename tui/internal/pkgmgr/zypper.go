@@ -0,0 +1,121 @@
+package pkgmgr
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// Zypper is the openSUSE/SLE package manager.
+type Zypper struct {
+	run     Runner
+	rolling bool // Tumbleweed and friends upgrade with dist-upgrade
+}
+
+// NewZypper returns a zypper backend that queries through r. Rolling
+// releases are upgraded with `zypper dup` instead of `zypper update`.
+func NewZypper(rolling bool, r Runner) *Zypper { return &Zypper{run: r, rolling: rolling} }
+
+// isRolling reports whether an os-release ID is a rolling openSUSE flavour.
+func isRolling(id string) bool {
+	switch id {
+	case "opensuse-tumbleweed", "opensuse-slowroll", "opensuse-microos":
+		return true
+	}
+	return false
+}
+
+func (z *Zypper) Name() string { return "zypper" }
+
+func (z *Zypper) Install(pkgs ...string) *exec.Cmd {
+	return sudo("zypper", append([]string{"--non-interactive", "install"}, pkgs...)...)
+}
+
+func (z *Zypper) Remove(pkgs ...string) *exec.Cmd {
+	return sudo("zypper", append([]string{"--non-interactive", "remove", "--clean-deps"}, pkgs...)...)
+}
+
+func (z *Zypper) IsInstalled(pkg string) (bool, error) {
+	return rpmInstalled(z.run, pkg)
+}
+
+func (z *Zypper) ListUpgradable() ([]Upgrade, error) {
+	out, err := z.run.Output("zypper", "--quiet", "list-updates")
+	if err != nil {
+		return nil, err
+	}
+	// S | Repository | Name | Current Version | Available Version | Arch
+	var ups []Upgrade
+	for _, row := range tableRows(out, 5) {
+		ups = append(ups, Upgrade{Name: row[2], Current: row[3], Available: row[4]})
+	}
+	return ups, nil
+}
+
+func (z *Zypper) Refresh() *exec.Cmd { return sudo("zypper", "--non-interactive", "refresh") }
+
+// Update locks excluded packages for the duration of the upgrade.
+func (z *Zypper) Update(exclude ...string) *exec.Cmd {
+	verb := "update"
+	if z.rolling {
+		verb = "dist-upgrade"
+	}
+	upgrade := sudo("zypper", "--non-interactive", verb)
+	if len(exclude) > 0 {
+		upgrade = holdDuring(
+			sudo("zypper", append([]string{"--non-interactive", "addlock"}, exclude...)...),
+			upgrade,
+			sudo("zypper", append([]string{"--non-interactive", "removelock"}, exclude...)...),
+		)
+	}
+	return Chain(z.Refresh(), upgrade)
+}
+
+func (z *Zypper) Clean() *exec.Cmd {
+	return sudo("zypper", "clean", "--all")
+}
+
+func (z *Zypper) CacheDirs() []string { return []string{"/var/cache/zypp/packages"} }
+
+func (z *Zypper) InstalledSize(pkgs ...string) (int64, error) {
+	return rpmSize(z.run, pkgs)
+}
+
+// Orphans lists unneeded packages (dependencies nothing requires). Zypper's
+// own "orphaned" packages are those in no repo, which are often hand-installed
+// RPMs, so they are not included.
+func (z *Zypper) Orphans() ([]string, error) {
+	out, err := z.run.Output("zypper", "--quiet", "packages", "--unneeded")
+	if err != nil {
+		return nil, err
+	}
+	// S | Repository | Name | Version | Arch
+	seen := make(map[string]bool)
+	var pkgs []string
+	for _, row := range tableRows(out, 3) {
+		if !seen[row[2]] {
+			seen[row[2]] = true
+			pkgs = append(pkgs, row[2])
+		}
+	}
+	return pkgs, nil
+}
+
+// tableRows parses zypper's "a | b | c" tables, skipping the header and
+// separator, and keeps rows with at least min columns.
+func tableRows(out []byte, min int) [][]string {
+	var rows [][]string
+	for i, l := range lines(out) {
+		if i == 0 || strings.HasPrefix(l, "--") {
+			continue
+		}
+		cols := strings.Split(l, "|")
+		if len(cols) < min {
+			continue
+		}
+		for j := range cols {
+			cols[j] = strings.TrimSpace(cols[j])
+		}
+		rows = append(rows, cols)
+	}
+	return rows
+}
@@ -0,0 +1,367 @@
+// Package rollback snapshots the files a bundle's install.sh touches so a
+// failed install can be undone.
+package rollback
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/reisset/mypctools/tui/internal/backup"
+	"github.com/reisset/mypctools/tui/internal/bundle"
+	"github.com/reisset/mypctools/tui/internal/logging"
+)
+
+// snapshotDir is the directory under logging.DataDir holding snapshots.
+const snapshotDir = "rollback"
+
+// maxAge is how long a snapshot left behind by a crash is kept.
+const maxAge = 24 * time.Hour
+
+// Path kinds recorded in a snapshot.
+const (
+	kindAbsent  = "absent"
+	kindSymlink = "symlink"
+	kindFile    = "file"
+	kindDir     = "dir"
+)
+
+// pathState is how one path looked before the install.
+type pathState struct {
+	Path   string `json:"path"`
+	Kind   string `json:"kind"`
+	Target string `json:"target,omitempty"` // Symlink target
+	Saved  string `json:"saved,omitempty"`  // Copy inside the snapshot (files and dirs)
+	Parent string `json:"parent,omitempty"` // Highest missing ancestor for absent paths
+}
+
+// Snapshot is the pre-install state of everything a bundle may change.
+type Snapshot struct {
+	Bundle string      `json:"bundle"`
+	Time   time.Time   `json:"time"`
+	Shell  string      `json:"shell,omitempty"` // Login shell from the passwd database
+	Paths  []pathState `json:"paths"`
+	dir    string
+}
+
+// Result describes what Restore did.
+type Result struct {
+	Restored int    // Paths put back
+	Shell    string // Login shell to switch back to ("" when unchanged)
+}
+
+// Take records the current state of every path the bundle's manifest tracks
+// (markers, links, copies, touches) plus originals from the backup ledger,
+// and the user's login shell.
+func Take(b bundle.Bundle) (*Snapshot, error) {
+	root := logging.DataDir()
+	if root == "" {
+		return nil, fmt.Errorf("failed to determine data directory")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	pruneStale(filepath.Join(root, snapshotDir))
+
+	s := &Snapshot{Bundle: b.ID, Time: time.Now(), Shell: loginShell()}
+	s.dir = filepath.Join(root, snapshotDir, b.ID+"-"+s.Time.Format("20060102-150405.000"))
+	if err := os.MkdirAll(filepath.Join(s.dir, "files"), 0700); err != nil {
+		return nil, err
+	}
+
+	for i, path := range trackedPaths(b, home) {
+		st, err := capture(path, filepath.Join(s.dir, "files", fmt.Sprint(i)), home)
+		if err != nil {
+			os.RemoveAll(s.dir)
+			return nil, fmt.Errorf("snapshot %s: %w", path, err)
+		}
+		s.Paths = append(s.Paths, st)
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err == nil {
+		err = os.WriteFile(filepath.Join(s.dir, "snapshot.json"), data, 0600)
+	}
+	if err != nil {
+		os.RemoveAll(s.dir)
+		return nil, err
+	}
+	return s, nil
+}
+
+// trackedPaths returns the absolute paths to snapshot, without duplicates.
+func trackedPaths(b bundle.Bundle, home string) []string {
+	seen := make(map[string]bool)
+	var paths []string
+	add := func(p string) {
+		if !seen[p] {
+			seen[p] = true
+			paths = append(paths, p)
+		}
+	}
+	for _, rel := range append(append(append([]string{}, b.Markers...), b.Links...), b.Touches...) {
+		add(filepath.Join(home, rel))
+	}
+	copies := make([]string, 0, len(b.Copies))
+	for dst := range b.Copies {
+		copies = append(copies, dst)
+	}
+	sort.Strings(copies)
+	for _, dst := range copies {
+		add(filepath.Join(home, dst))
+	}
+	if entries, err := backup.ForBundle(b.ID); err == nil {
+		for _, e := range entries {
+			add(e.Original)
+		}
+	}
+	return paths
+}
+
+// capture records path's state, copying files and directories to saved.
+func capture(path, saved, home string) (pathState, error) {
+	st := pathState{Path: path}
+	info, err := os.Lstat(path)
+	switch {
+	case os.IsNotExist(err):
+		st.Kind = kindAbsent
+		st.Parent = missingAncestor(path, home)
+		return st, nil
+	case err != nil:
+		return st, err
+	case info.Mode()&os.ModeSymlink != 0:
+		st.Kind = kindSymlink
+		st.Target, err = os.Readlink(path)
+		return st, err
+	case info.IsDir():
+		st.Kind = kindDir
+	default:
+		st.Kind = kindFile
+	}
+	st.Saved = saved
+	return st, copyTree(path, saved)
+}
+
+// missingAncestor returns the highest directory between home and path that
+// does not exist yet, or "" if the parent already exists.
+func missingAncestor(path, home string) string {
+	var highest string
+	for dir := filepath.Dir(path); dir != home && strings.HasPrefix(dir, home+"/"); dir = filepath.Dir(dir) {
+		if _, err := os.Lstat(dir); err == nil {
+			break
+		}
+		highest = dir
+	}
+	return highest
+}
+
+// Restore puts every path back the way it was and deletes backups the
+// install made of them, since the originals are back in place. Packages the
+// script installed are left alone.
+func (s *Snapshot) Restore() (Result, error) {
+	var res Result
+	var errs []string
+	restored := make(map[string]bool, len(s.Paths))
+	for i := len(s.Paths) - 1; i >= 0; i-- {
+		st := s.Paths[i]
+		changed, err := st.restore()
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", st.Path, err))
+			continue
+		}
+		restored[st.Path] = true
+		if changed {
+			res.Restored++
+		}
+	}
+
+	// A backup of a path that is back in its original state is redundant.
+	if entries, err := backup.ForBundle(s.Bundle); err == nil {
+		for _, e := range entries {
+			if restored[e.Original] && !e.Time.Before(s.Time.Truncate(time.Second)) {
+				backup.Prune(e) //nolint:errcheck
+			}
+		}
+	}
+
+	if s.Shell != "" && loginShell() != s.Shell {
+		res.Shell = s.Shell
+	}
+	if len(errs) > 0 {
+		return res, fmt.Errorf("could not restore %s", strings.Join(errs, "; "))
+	}
+	return res, nil
+}
+
+// restore puts one path back. Reports whether anything had to change.
+func (st pathState) restore() (bool, error) {
+	info, err := os.Lstat(st.Path)
+	exists := err == nil
+
+	switch st.Kind {
+	case kindAbsent:
+		if exists {
+			if err := os.RemoveAll(st.Path); err != nil {
+				return false, err
+			}
+		}
+		if st.Parent != "" {
+			removeEmptyDirs(filepath.Dir(st.Path), st.Parent)
+		}
+		return exists, nil
+
+	case kindSymlink:
+		if exists && info.Mode()&os.ModeSymlink != 0 {
+			if target, _ := os.Readlink(st.Path); target == st.Target {
+				return false, nil
+			}
+		}
+		if err := os.RemoveAll(st.Path); err != nil {
+			return false, err
+		}
+		if err := os.MkdirAll(filepath.Dir(st.Path), 0755); err != nil {
+			return false, err
+		}
+		return true, os.Symlink(st.Target, st.Path)
+
+	default:
+		if exists && st.Kind == kindFile && info.Mode().IsRegular() && sameContent(st.Path, st.Saved) {
+			return false, nil
+		}
+		if err := os.RemoveAll(st.Path); err != nil {
+			return false, err
+		}
+		if err := os.MkdirAll(filepath.Dir(st.Path), 0755); err != nil {
+			return false, err
+		}
+		return true, copyTree(st.Saved, st.Path)
+	}
+}
+
+// removeEmptyDirs removes dir and its parents up to and including top, as
+// long as they are empty.
+func removeEmptyDirs(dir, top string) {
+	for {
+		if os.Remove(dir) != nil || dir == top {
+			return
+		}
+		dir = filepath.Dir(dir)
+	}
+}
+
+// Discard deletes the snapshot from disk.
+func (s *Snapshot) Discard() error {
+	if s == nil || s.dir == "" {
+		return nil
+	}
+	return os.RemoveAll(s.dir)
+}
+
+// ShellCommand returns the command that switches the login shell back.
+// It needs sudo, so run it where the user can type a password.
+func ShellCommand(shell string) *exec.Cmd {
+	name := os.Getenv("USER")
+	if u, err := user.Current(); err == nil {
+		name = u.Username
+	}
+	return exec.Command("sudo", "chsh", "-s", shell, name)
+}
+
+// loginShell reads the current user's shell from the passwd database
+// (more reliable than $SHELL, which reflects the session).
+func loginShell() string {
+	u, err := user.Current()
+	if err != nil {
+		return ""
+	}
+	out, err := exec.Command("getent", "passwd", u.Username).Output()
+	if err != nil {
+		return ""
+	}
+	fields := strings.Split(strings.TrimSpace(string(out)), ":")
+	if len(fields) < 7 {
+		return ""
+	}
+	return fields[6]
+}
+
+// pruneStale removes snapshots older than maxAge (left by a crash).
+func pruneStale(dir string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		if info, err := e.Info(); err == nil && time.Since(info.ModTime()) > maxAge {
+			os.RemoveAll(filepath.Join(dir, e.Name()))
+		}
+	}
+}
+
+// copyTree copies a file, symlink or directory tree from src to dst,
+// preserving permissions.
+func copyTree(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		switch {
+		case info.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		case info.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm()|0700)
+		default:
+			return copyFile(path, target, info.Mode().Perm())
+		}
+	})
+}
+
+// sameContent reports whether two regular files have identical bytes.
+func sameContent(a, b string) bool {
+	da, err := os.ReadFile(a)
+	if err != nil {
+		return false
+	}
+	db, err := os.ReadFile(b)
+	return err == nil && bytes.Equal(da, db)
+}
+
+func copyFile(src, dst string, perm fs.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
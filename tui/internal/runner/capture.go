@@ -0,0 +1,22 @@
+package runner
+
+import "sync"
+
+var captureMu sync.RWMutex
+
+var captureEnabled bool
+
+// CaptureEnabled reports whether commands run inside the in-TUI output pane.
+func CaptureEnabled() bool {
+	captureMu.RLock()
+	defer captureMu.RUnlock()
+	return captureEnabled
+}
+
+// SetCaptureEnabled sets the capture preference. The preference itself lives
+// in config.toml.
+func SetCaptureEnabled(on bool) {
+	captureMu.Lock()
+	defer captureMu.Unlock()
+	captureEnabled = on
+}
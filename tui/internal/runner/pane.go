@@ -0,0 +1,240 @@
+package runner
+
+import (
+	"os/exec"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+	"github.com/reisset/mypctools/tui/internal/app"
+	"github.com/reisset/mypctools/tui/internal/state"
+	"github.com/reisset/mypctools/tui/internal/theme"
+)
+
+// StartedMsg reports that a command is running in a PTY.
+type StartedMsg struct{ run *Run }
+
+type outputMsg struct {
+	id   int64
+	data []byte
+}
+
+type exitMsg struct {
+	id  int64
+	err error
+}
+
+// Pane streams a command's output into a scrollable viewport. Screens embed
+// one and start commands with Exec; when the command exits the pane emits
+// app.ExecDoneMsg, just like tea.ExecProcess, so completion handling is the
+// same whether or not output capture is enabled.
+type Pane struct {
+	shared     *state.Shared
+	run        *Run
+	running    bool
+	buf        *screenBuffer
+	viewport   viewport.Model
+	follow     bool // Stick to the bottom as output arrives
+	transcript string
+}
+
+// NewPane creates an idle output pane.
+func NewPane(shared *state.Shared) Pane {
+	return Pane{shared: shared}
+}
+
+// Exec runs cmd. With output capture enabled it runs in a PTY inside the
+// pane; otherwise the terminal is handed over with tea.ExecProcess.
+// Either way the screen receives app.ExecDoneMsg when it exits.
+func (p Pane) Exec(cmd *exec.Cmd, label string) tea.Cmd {
+	if !CaptureEnabled() {
+		return tea.ExecProcess(cmd, func(err error) tea.Msg {
+			return app.ExecDoneMsg{Err: err}
+		})
+	}
+	cols, rows := p.size()
+	return func() tea.Msg {
+		r, err := start(cmd, label, cols, rows)
+		if err != nil {
+			return app.ExecDoneMsg{Err: err}
+		}
+		return StartedMsg{run: r}
+	}
+}
+
+// Update handles pane messages and, while a command runs, key input.
+// handled is false for messages the screen should process itself.
+func (p Pane) Update(msg tea.Msg) (Pane, tea.Cmd, bool) {
+	switch msg := msg.(type) {
+	case StartedMsg:
+		p.run = msg.run
+		p.running = true
+		p.transcript = msg.run.transcript
+		p.buf = &screenBuffer{}
+		p.follow = true
+		cols, rows := p.size()
+		p.viewport = viewport.New(cols, rows)
+		return p, p.wait(), true
+
+	case outputMsg:
+		if p.run == nil || msg.id != p.run.id {
+			return p, nil, true
+		}
+		p.buf.Write(msg.data)
+		p.refresh()
+		return p, p.wait(), true
+
+	case exitMsg:
+		if p.run == nil || msg.id != p.run.id {
+			return p, nil, true
+		}
+		p.running = false
+		err := msg.err
+		return p, func() tea.Msg { return app.ExecDoneMsg{Err: err} }, true
+
+	case tea.WindowSizeMsg:
+		if p.running {
+			cols, rows := p.size()
+			p.viewport.Width, p.viewport.Height = cols, rows
+			p.run.resize(cols, rows)
+			p.refresh()
+		}
+		return p, nil, false
+
+	case tea.KeyMsg:
+		if !p.running {
+			return p, nil, false
+		}
+		switch msg.String() {
+		case "pgup", "shift+up":
+			p.viewport.HalfViewUp()
+			p.follow = false
+		case "pgdown", "shift+down":
+			p.viewport.HalfViewDown()
+			p.follow = p.viewport.AtBottom()
+		case "home":
+			p.viewport.GotoTop()
+			p.follow = false
+		case "end":
+			p.viewport.GotoBottom()
+			p.follow = true
+		default:
+			if b := keyBytes(msg); b != nil {
+				p.run.write(b)
+				p.viewport.GotoBottom()
+				p.follow = true
+			}
+		}
+		return p, nil, true
+	}
+	return p, nil, false
+}
+
+// wait blocks for the next chunk of output or the exit status.
+func (p Pane) wait() tea.Cmd {
+	r := p.run
+	return func() tea.Msg {
+		data, ok := <-r.out
+		if !ok {
+			return exitMsg{id: r.id, err: r.err}
+		}
+		return outputMsg{id: r.id, data: data}
+	}
+}
+
+func (p *Pane) refresh() {
+	p.viewport.SetContent(clip(p.buf.Lines(), p.viewport.Width))
+	if p.follow {
+		p.viewport.GotoBottom()
+	}
+}
+
+// size returns the PTY dimensions: the content area minus the status line.
+func (p Pane) size() (cols, rows int) {
+	cols = p.shared.TerminalWidth - 4
+	if cols < 20 {
+		cols = 76
+	}
+	rows = p.shared.ContentHeight - 2
+	if rows < 5 {
+		rows = 5
+	}
+	return cols, rows
+}
+
+// Running reports whether a command is executing in the pane.
+func (p Pane) Running() bool { return p.running }
+
+// TranscriptPath returns the transcript of the latest run, or "" if output
+// was not captured.
+func (p Pane) TranscriptPath() string { return p.transcript }
+
+// View renders the output viewport with a status line.
+func (p Pane) View(status string) string {
+	width := p.shared.TerminalWidth
+	if width == 0 {
+		width = 80
+	}
+	hint := "pgup/pgdn scroll"
+	if !p.follow {
+		hint = "end follow output"
+	}
+	line := theme.MutedStyle().Render(status + "  ·  " + hint)
+	return lipgloss.JoinVertical(lipgloss.Left,
+		lipgloss.NewStyle().Width(width).Align(lipgloss.Center).Render(line),
+		lipgloss.NewStyle().PaddingLeft(2).Render(p.viewport.View()),
+	)
+}
+
+// ShortHelp returns footer hints while a command is running.
+func (p Pane) ShortHelp() []string {
+	return []string{"pgup/pgdn scroll", "end follow", "keys and ctrl+c go to command"}
+}
+
+// clip joins lines, truncating each to width so the viewport never wraps.
+func clip(lines []string, width int) string {
+	out := make([]string, len(lines))
+	for i, l := range lines {
+		out[i] = runewidth.Truncate(l, width, "")
+	}
+	return strings.Join(out, "\n")
+}
+
+// keyBytes translates a key press into the bytes a terminal would send.
+func keyBytes(msg tea.KeyMsg) []byte {
+	switch msg.Type {
+	case tea.KeyRunes:
+		return []byte(string(msg.Runes))
+	case tea.KeySpace:
+		return []byte(" ")
+	case tea.KeyEnter:
+		return []byte("\r")
+	case tea.KeyBackspace:
+		return []byte{0x7f}
+	case tea.KeyTab:
+		return []byte("\t")
+	case tea.KeyEsc:
+		return []byte{0x1b}
+	case tea.KeyUp:
+		return []byte("\x1b[A")
+	case tea.KeyDown:
+		return []byte("\x1b[B")
+	case tea.KeyRight:
+		return []byte("\x1b[C")
+	case tea.KeyLeft:
+		return []byte("\x1b[D")
+	case tea.KeyCtrlC:
+		return []byte{0x03}
+	case tea.KeyCtrlD:
+		return []byte{0x04}
+	case tea.KeyCtrlU:
+		return []byte{0x15}
+	case tea.KeyCtrlZ:
+		return []byte{0x1a}
+	case tea.KeyCtrlBackslash:
+		return []byte{0x1c}
+	}
+	return nil
+}
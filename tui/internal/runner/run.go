@@ -0,0 +1,190 @@
+package runner
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/creack/pty"
+	"github.com/reisset/mypctools/tui/internal/logging"
+)
+
+// maxTranscripts is how many run transcripts are kept on disk.
+const maxTranscripts = 50
+
+var nextID atomic.Int64
+
+// active holds runs whose child hasn't exited, so StopAll can end them.
+var active sync.Map // *Run → struct{}
+
+// Run is a command running inside a pseudo-terminal. Everything it prints is
+// streamed to the pane and teed into a transcript file.
+type Run struct {
+	id         int64
+	label      string
+	cmd        *exec.Cmd
+	pty        *os.File
+	out        chan []byte
+	err        error // Exit error, valid once out is closed
+	transcript string
+}
+
+// start launches cmd on a new PTY of the given size.
+func start(cmd *exec.Cmd, label string, cols, rows int) (*Run, error) {
+	f, err := pty.StartWithSize(cmd, &pty.Winsize{Rows: uint16(rows), Cols: uint16(cols)})
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Run{
+		id:    nextID.Add(1),
+		label: label,
+		cmd:   cmd,
+		pty:   f,
+		out:   make(chan []byte, 64),
+	}
+
+	// A transcript is best-effort: the run proceeds without one if the data
+	// directory is unavailable.
+	log, path := createTranscript(label, cmd)
+	r.transcript = path
+
+	active.Store(r, struct{}{})
+	go r.pump(log)
+	return r, nil
+}
+
+// pump copies PTY output to the transcript and the out channel until the
+// child exits, then records the exit error and closes out.
+func (r *Run) pump(log *os.File) {
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := r.pty.Read(buf)
+		if n > 0 {
+			chunk := append([]byte(nil), buf[:n]...)
+			if log != nil {
+				log.Write(chunk)
+			}
+			r.out <- chunk
+		}
+		if err != nil {
+			// Linux returns EIO once the child side closes.
+			break
+		}
+	}
+	r.err = r.cmd.Wait()
+	active.Delete(r)
+	r.pty.Close()
+	if log != nil {
+		status := "ok"
+		if r.err != nil {
+			status = r.err.Error()
+		}
+		fmt.Fprintf(log, "\n[%s] exit: %s\n", time.Now().Format("2006-01-02 15:04:05"), status)
+		log.Close()
+	}
+	close(r.out)
+}
+
+// write sends input typed in the pane to the child.
+func (r *Run) write(p []byte) {
+	r.pty.Write(p)
+}
+
+// StopAll hangs up every command still running in a PTY. The child leads its
+// own session (pty.Start sets Setsid), so the whole process group is
+// signalled. Called on exit so quitting never leaves commands running
+// unseen.
+func StopAll() {
+	active.Range(func(key, _ any) bool {
+		if p := key.(*Run).cmd.Process; p != nil {
+			syscall.Kill(-p.Pid, syscall.SIGHUP)
+		}
+		return true
+	})
+}
+
+// resize updates the PTY window size.
+func (r *Run) resize(cols, rows int) {
+	pty.Setsize(r.pty, &pty.Winsize{Rows: uint16(rows), Cols: uint16(cols)})
+}
+
+// TranscriptDir returns ~/.local/share/mypctools/runs.
+func TranscriptDir() string {
+	dir := logging.DataDir()
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, "runs")
+}
+
+// transcripts lists transcript files oldest first (names start with a timestamp).
+func transcripts() []string {
+	dir := TranscriptDir()
+	if dir == "" {
+		return nil
+	}
+	files, _ := filepath.Glob(filepath.Join(dir, "*.log"))
+	sort.Strings(files)
+	return files
+}
+
+func createTranscript(label string, cmd *exec.Cmd) (*os.File, string) {
+	dir := TranscriptDir()
+	if dir == "" {
+		return nil, ""
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, ""
+	}
+	pruneTranscripts(maxTranscripts - 1)
+
+	now := time.Now()
+	name := fmt.Sprintf("%s-%s.log", now.Format("20060102-150405.000"), slug(label))
+	path := filepath.Join(dir, name)
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, ""
+	}
+	fmt.Fprintf(f, "[%s] %s\n$ %s\n\n", now.Format("2006-01-02 15:04:05"), label, strings.Join(cmd.Args, " "))
+	return f, path
+}
+
+// pruneTranscripts deletes the oldest transcripts so at most keep remain.
+func pruneTranscripts(keep int) {
+	files := transcripts()
+	for len(files) > keep {
+		os.Remove(files[0])
+		files = files[1:]
+	}
+}
+
+// slug turns a label into a filename-safe fragment.
+func slug(label string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(label) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	s := strings.TrimSuffix(b.String(), "-")
+	if s == "" {
+		return "run"
+	}
+	if len(s) > 40 {
+		s = strings.TrimSuffix(s[:40], "-")
+	}
+	return s
+}
@@ -0,0 +1,170 @@
+package runner
+
+import (
+	"unicode/utf8"
+)
+
+// maxLines caps how much scrollback the pane and transcript viewer keep.
+const maxLines = 5000
+
+type escState int
+
+const (
+	escNone    escState = iota
+	escStart            // After ESC
+	escCSI              // Inside ESC [ ... final
+	escOSC              // Inside ESC ] ... BEL or ESC \
+	escOSCEnd           // ESC seen inside an OSC
+	escCharset          // ESC ( or ESC ) — one more byte follows
+)
+
+// screenBuffer turns raw terminal output into plain lines. It understands
+// just enough of a terminal to keep progress bars readable: \r rewinds the
+// current line, \b steps back, ESC [ K erases to end of line, and every
+// other escape sequence (colors, cursor moves, titles) is dropped.
+type screenBuffer struct {
+	lines   []string
+	cur     []rune
+	col     int
+	esc     escState
+	params  []byte
+	pending []byte // Incomplete UTF-8 sequence carried over from the last write
+}
+
+// Lines renders raw terminal output (e.g. a saved transcript) into plain lines.
+func Lines(data []byte) []string {
+	var b screenBuffer
+	b.Write(data)
+	return b.Lines()
+}
+
+// Write feeds raw terminal output into the buffer.
+func (b *screenBuffer) Write(data []byte) {
+	if len(b.pending) > 0 {
+		data = append(b.pending, data...)
+		b.pending = nil
+	}
+	for len(data) > 0 {
+		c := data[0]
+		if b.esc != escNone || c < utf8.RuneSelf {
+			b.writeByte(c)
+			data = data[1:]
+			continue
+		}
+		if !utf8.FullRune(data) {
+			b.pending = append([]byte(nil), data...)
+			return
+		}
+		r, size := utf8.DecodeRune(data)
+		b.put(r)
+		data = data[size:]
+	}
+}
+
+func (b *screenBuffer) writeByte(c byte) {
+	switch b.esc {
+	case escStart:
+		switch c {
+		case '[':
+			b.esc = escCSI
+			b.params = b.params[:0]
+		case ']':
+			b.esc = escOSC
+		case '(', ')':
+			b.esc = escCharset
+		default:
+			b.esc = escNone
+		}
+		return
+	case escCharset:
+		b.esc = escNone
+		return
+	case escCSI:
+		if c >= 0x40 && c <= 0x7e {
+			b.esc = escNone
+			b.csi(c)
+			return
+		}
+		b.params = append(b.params, c)
+		return
+	case escOSC:
+		switch c {
+		case 0x07:
+			b.esc = escNone
+		case 0x1b:
+			b.esc = escOSCEnd
+		}
+		return
+	case escOSCEnd:
+		b.esc = escNone
+		return
+	}
+
+	switch c {
+	case 0x1b:
+		b.esc = escStart
+	case '\n':
+		b.newline()
+	case '\r':
+		b.col = 0
+	case '\b':
+		if b.col > 0 {
+			b.col--
+		}
+	case '\t':
+		for {
+			b.put(' ')
+			if b.col%8 == 0 {
+				break
+			}
+		}
+	default:
+		if c >= 0x20 && c != 0x7f {
+			b.put(rune(c))
+		}
+	}
+}
+
+// csi applies the few control sequences that change line content.
+func (b *screenBuffer) csi(final byte) {
+	if final != 'K' {
+		return
+	}
+	switch string(b.params) {
+	case "", "0":
+		if b.col < len(b.cur) {
+			b.cur = b.cur[:b.col]
+		}
+	case "2":
+		b.cur = b.cur[:0]
+	}
+}
+
+func (b *screenBuffer) put(r rune) {
+	if b.col < len(b.cur) {
+		b.cur[b.col] = r
+	} else {
+		for len(b.cur) < b.col {
+			b.cur = append(b.cur, ' ')
+		}
+		b.cur = append(b.cur, r)
+	}
+	b.col++
+}
+
+func (b *screenBuffer) newline() {
+	b.lines = append(b.lines, string(b.cur))
+	if len(b.lines) > maxLines {
+		b.lines = b.lines[len(b.lines)-maxLines:]
+	}
+	b.cur = b.cur[:0]
+	b.col = 0
+}
+
+// Lines returns the completed lines plus the line in progress, if any.
+func (b *screenBuffer) Lines() []string {
+	if len(b.cur) == 0 {
+		return b.lines
+	}
+	return append(b.lines[:len(b.lines):len(b.lines)], string(b.cur))
+}
@@ -0,0 +1,256 @@
+package backups
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/reisset/mypctools/tui/internal/app"
+	"github.com/reisset/mypctools/tui/internal/backup"
+	"github.com/reisset/mypctools/tui/internal/bundle"
+	"github.com/reisset/mypctools/tui/internal/logging"
+	"github.com/reisset/mypctools/tui/internal/state"
+	"github.com/reisset/mypctools/tui/internal/theme"
+	"github.com/reisset/mypctools/tui/internal/ui"
+)
+
+type loadedMsg struct {
+	entries []backup.Entry
+	err     error
+}
+
+type actionDoneMsg struct {
+	text string
+	err  error
+}
+
+// Model lists files that install scripts displaced, for one bundle or all.
+type Model struct {
+	shared     *state.Shared
+	bundleID   string // "" for every bundle
+	entries    []backup.Entry
+	cursor     int
+	loaded     bool
+	err        error
+	confirming string // "restore" or "prune" while asking for confirmation
+	status     string
+	statusErr  bool
+}
+
+// New creates a backups screen. An empty bundleID lists backups for every bundle.
+func New(shared *state.Shared, bundleID string) Model {
+	return Model{shared: shared, bundleID: bundleID}
+}
+
+// Init (re)loads the list, so it is fresh after returning from a diff.
+func (m Model) Init() tea.Cmd {
+	id := m.bundleID
+	return func() tea.Msg {
+		var entries []backup.Entry
+		var err error
+		if id == "" {
+			entries, err = backup.List()
+		} else {
+			entries, err = backup.ForBundle(id)
+		}
+		sortByBundle(entries)
+		return loadedMsg{entries: entries, err: err}
+	}
+}
+
+// sortByBundle groups entries by bundle in display order, keeping newest
+// first within each bundle.
+func sortByBundle(entries []backup.Entry) {
+	rank := make(map[string]int)
+	for i, b := range bundle.All() {
+		rank[b.ID] = i
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return rank[entries[i].Bundle] < rank[entries[j].Bundle]
+	})
+}
+
+func (m Model) Update(msg tea.Msg) (app.Screen, tea.Cmd) {
+	switch msg := msg.(type) {
+	case loadedMsg:
+		m.loaded = true
+		m.entries = msg.entries
+		m.err = msg.err
+		if m.cursor >= len(m.entries) {
+			m.cursor = max(len(m.entries)-1, 0)
+		}
+		return m, nil
+
+	case actionDoneMsg:
+		m.status = msg.text
+		m.statusErr = msg.err != nil
+		if msg.err != nil {
+			m.status = msg.err.Error()
+		}
+		return m, m.Init()
+
+	case tea.KeyMsg:
+		if m.confirming != "" {
+			switch msg.String() {
+			case "y", "Y":
+				action := m.confirming
+				m.confirming = ""
+				return m, m.apply(action, m.entries[m.cursor])
+			case "n", "N", "esc":
+				m.confirming = ""
+			}
+			return m, nil
+		}
+		if !m.loaded || len(m.entries) == 0 {
+			return m, nil
+		}
+
+		switch msg.String() {
+		case "down":
+			m.cursor++
+			if m.cursor >= len(m.entries) {
+				m.cursor = 0
+			}
+		case "up":
+			m.cursor--
+			if m.cursor < 0 {
+				m.cursor = len(m.entries) - 1
+			}
+		case "enter", " ", "d":
+			return m, app.Navigate(NewDiff(m.shared, m.entries[m.cursor]))
+		case "r":
+			m.confirming = "restore"
+			m.status = ""
+		case "x", "delete":
+			m.confirming = "prune"
+			m.status = ""
+		}
+	}
+	return m, nil
+}
+
+// apply restores or prunes e in the background.
+func (m Model) apply(action string, e backup.Entry) tea.Cmd {
+	return func() tea.Msg {
+		name := tildePath(e.Original)
+		if action == "restore" {
+			if err := backup.Restore(e); err != nil {
+				return actionDoneMsg{err: fmt.Errorf("restore failed: %w", err)}
+			}
+			logging.LogAction(fmt.Sprintf("Restored backup %s → %s", e.Backup, e.Original)) //nolint:errcheck
+			return actionDoneMsg{text: "Restored " + name}
+		}
+		if err := backup.Prune(e); err != nil {
+			return actionDoneMsg{err: fmt.Errorf("delete failed: %w", err)}
+		}
+		logging.LogAction(fmt.Sprintf("Deleted backup %s", e.Backup)) //nolint:errcheck
+		return actionDoneMsg{text: "Deleted backup of " + name}
+	}
+}
+
+func (m Model) View() string {
+	width := m.shared.TerminalWidth
+	if width == 0 {
+		width = 80
+	}
+	center := func(s string) string {
+		return lipgloss.NewStyle().Width(width).Align(lipgloss.Center).Render(s)
+	}
+
+	if !m.loaded {
+		return center(theme.MutedStyle().Render("Looking for backups..."))
+	}
+	if m.err != nil {
+		return center(theme.ErrorStyle().Render("Could not read backups: " + m.err.Error()))
+	}
+	if len(m.entries) == 0 {
+		parts := []string{}
+		if m.status != "" {
+			parts = append(parts, center(m.renderStatus()), "")
+		}
+		parts = append(parts, center(theme.MutedStyle().Render("No backups — nothing has been displaced")))
+		return lipgloss.JoinVertical(lipgloss.Left, parts...)
+	}
+
+	items := make([]ui.ListItem, len(m.entries))
+	for i, e := range m.entries {
+		desc := filepath.Base(e.Backup)
+		if !e.Time.IsZero() {
+			desc = e.Time.Format("2006-01-02 15:04") + " · " + desc
+		}
+		items[i] = ui.ListItem{
+			Icon:        "↺",
+			Label:       tildePath(e.Original),
+			Suffix:      theme.MutedStyle().Render(e.Bundle),
+			Description: desc,
+		}
+	}
+	height := (m.shared.ContentHeight - 4) / 2 // Two lines per item
+	list := ui.RenderList(items, m.cursor, ui.ListConfig{
+		Width:         72,
+		MaxInnerWidth: 72,
+		Height:        max(height, 2),
+	})
+
+	parts := []string{center(list)}
+	if m.confirming != "" {
+		verb := "Restore"
+		detail := "the current file is kept as a new backup"
+		if m.confirming == "prune" {
+			verb = "Delete backup of"
+			detail = "this cannot be undone"
+		}
+		e := m.entries[m.cursor]
+		parts = append(parts, "",
+			center(theme.WarningStyle().Render(fmt.Sprintf("%s %s?", verb, tildePath(e.Original)))),
+			center(theme.MutedStyle().Render(detail+" · y confirm · n cancel")))
+	} else if m.status != "" {
+		parts = append(parts, "", center(m.renderStatus()))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, parts...)
+}
+
+func (m Model) renderStatus() string {
+	if m.statusErr {
+		return theme.ErrorStyle().Render(m.status)
+	}
+	icons := theme.GetIcons()
+	return theme.SuccessStyle().Render(icons.Check + " " + m.status)
+}
+
+func (m Model) Title() string {
+	if m.bundleID != "" {
+		if b, ok := bundle.Find(m.bundleID); ok {
+			return "Backups · " + b.Name
+		}
+	}
+	return "Backups"
+}
+
+func (m Model) HandlesBack() bool { return m.confirming != "" }
+
+func (m Model) ShortHelp() []string {
+	if m.confirming != "" {
+		return []string{"y confirm", "n cancel"}
+	}
+	if len(m.entries) == 0 {
+		return []string{}
+	}
+	return []string{"enter diff", "r restore", "x delete"}
+}
+
+// tildePath shows paths under $HOME as ~/...
+func tildePath(path string) string {
+	home, err := os.UserHomeDir()
+	if err != nil || home == "" {
+		return path
+	}
+	if rest, ok := strings.CutPrefix(path, home+"/"); ok {
+		return "~/" + rest
+	}
+	return path
+}
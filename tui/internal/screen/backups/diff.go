@@ -0,0 +1,150 @@
+package backups
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+	"github.com/reisset/mypctools/tui/internal/app"
+	"github.com/reisset/mypctools/tui/internal/backup"
+	"github.com/reisset/mypctools/tui/internal/state"
+	"github.com/reisset/mypctools/tui/internal/theme"
+)
+
+type diffLoadedMsg struct {
+	diff string
+	err  error
+}
+
+// DiffModel shows how a backup differs from what is at its original path now.
+type DiffModel struct {
+	shared   *state.Shared
+	entry    backup.Entry
+	lines    []string
+	loaded   bool
+	err      error
+	viewport viewport.Model
+}
+
+// NewDiff creates a diff viewer for one backup.
+func NewDiff(shared *state.Shared, e backup.Entry) DiffModel {
+	m := DiffModel{shared: shared, entry: e}
+	m.viewport = viewport.New(m.size())
+	return m
+}
+
+func (m DiffModel) Init() tea.Cmd {
+	if m.loaded {
+		return nil
+	}
+	e := m.entry
+	return func() tea.Msg {
+		diff, err := backup.Diff(e)
+		return diffLoadedMsg{diff: diff, err: err}
+	}
+}
+
+func (m DiffModel) Update(msg tea.Msg) (app.Screen, tea.Cmd) {
+	switch msg := msg.(type) {
+	case diffLoadedMsg:
+		m.loaded = true
+		m.err = msg.err
+		if d := strings.TrimRight(msg.diff, "\n"); d != "" {
+			m.lines = strings.Split(d, "\n")
+		}
+		m.refresh()
+	case tea.WindowSizeMsg:
+		m.viewport.Width, m.viewport.Height = m.size()
+		m.refresh()
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "up", "k":
+			m.viewport.LineUp(1)
+		case "down", "j":
+			m.viewport.LineDown(1)
+		case "pgup":
+			m.viewport.HalfViewUp()
+		case "pgdown", " ":
+			m.viewport.HalfViewDown()
+		case "home":
+			m.viewport.GotoTop()
+		case "end":
+			m.viewport.GotoBottom()
+		}
+	}
+	return m, nil
+}
+
+// refresh colours diff lines: removals are what only the backup has.
+func (m *DiffModel) refresh() {
+	out := make([]string, len(m.lines))
+	for i, l := range m.lines {
+		l = runewidth.Truncate(strings.ReplaceAll(l, "\t", "    "), m.viewport.Width, "")
+		switch {
+		case strings.HasPrefix(l, "+++"), strings.HasPrefix(l, "---"):
+			out[i] = theme.MutedStyle().Render(l)
+		case strings.HasPrefix(l, "@@"):
+			out[i] = lipgloss.NewStyle().Foreground(lipgloss.Color(theme.Current.Primary)).Render(l)
+		case strings.HasPrefix(l, "+"):
+			out[i] = theme.SuccessStyle().Render(l)
+		case strings.HasPrefix(l, "-"):
+			out[i] = theme.ErrorStyle().Render(l)
+		default:
+			out[i] = l
+		}
+	}
+	m.viewport.SetContent(strings.Join(out, "\n"))
+}
+
+func (m DiffModel) size() (int, int) {
+	w := m.shared.TerminalWidth - 4
+	if w < 20 {
+		w = 76
+	}
+	h := m.shared.ContentHeight - 2
+	if h < 5 {
+		h = 5
+	}
+	return w, h
+}
+
+func (m DiffModel) View() string {
+	width := m.shared.TerminalWidth
+	if width == 0 {
+		width = 80
+	}
+	center := func(s string) string {
+		return lipgloss.NewStyle().Width(width).Align(lipgloss.Center).Render(s)
+	}
+
+	if !m.loaded {
+		return center(theme.MutedStyle().Render("Comparing..."))
+	}
+	if m.err != nil {
+		return center(theme.ErrorStyle().Render(m.err.Error()))
+	}
+	if len(m.lines) == 0 {
+		icons := theme.GetIcons()
+		return center(theme.SuccessStyle().Render(icons.Check + " Identical to the current file — safe to delete"))
+	}
+
+	status := theme.MutedStyle().Render("- only in backup  ·  + only in " + tildePath(m.entry.Original))
+	return lipgloss.JoinVertical(lipgloss.Left,
+		center(status),
+		lipgloss.NewStyle().PaddingLeft(2).Render(m.viewport.View()),
+	)
+}
+
+func (m DiffModel) Title() string { return "Diff " + filepath.Base(m.entry.Backup) }
+
+func (m DiffModel) HandlesBack() bool { return false }
+
+func (m DiffModel) ShortHelp() []string {
+	if len(m.lines) == 0 {
+		return []string{}
+	}
+	return []string{"↑↓ scroll", "pgup/pgdn page", "home/end jump"}
+}
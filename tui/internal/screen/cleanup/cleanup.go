@@ -2,12 +2,13 @@ package cleanup
 
 import (
 	"fmt"
-	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/reisset/mypctools/tui/internal/app"
 	"github.com/reisset/mypctools/tui/internal/logging"
+	"github.com/reisset/mypctools/tui/internal/runner"
+	"github.com/reisset/mypctools/tui/internal/screen/transcript"
 	"github.com/reisset/mypctools/tui/internal/state"
 	"github.com/reisset/mypctools/tui/internal/system"
 	"github.com/reisset/mypctools/tui/internal/theme"
@@ -17,169 +18,263 @@ import (
 type phase int
 
 const (
-	phasePackageCleanup phase = iota
-	phaseAskUserCache
-	phaseClearingCache
+	phaseScan   phase = iota // Measuring every target
+	phaseSelect              // User picks what to clean
+	phaseClean               // Cleaning the selected targets one by one
+	phaseRemeasure
 	phaseDone
 )
 
-type cacheClearDoneMsg struct{ err error }
+// measuredMsg carries target sizes, before or after cleaning.
+type measuredMsg struct {
+	sizes []int64
+	errs  []error
+}
 
-type action int
+// clearedMsg reports an in-process target clear.
+type clearedMsg struct{ err error }
 
-const (
-	actionYes action = iota
-	actionNo
-)
-
-// Model handles the system cleanup screen.
+// Model handles the system cleanup screen: it measures each target, lets the
+// user pick which to clean, and reports the space reclaimed per target.
 type Model struct {
-	shared       *state.Shared
-	phase        phase
-	cursor       action
-	pkgErr       error
-	cacheCleared bool
-	cacheErr     error
-	shimmer      ui.Shimmer
-	fadeup       ui.FadeUp
+	shared   *state.Shared
+	phase    phase
+	targets  []system.Target
+	before   []int64
+	scanErrs []error
+	after    []int64
+	selected []bool
+	ran      []bool  // Target was cleaned (or attempted)
+	errs     []error // Clean failures
+	cursor   int
+	current  int    // Target being cleaned
+	logPath  string // Transcript of the first failed command
+	shimmer  ui.Shimmer
+	fadeup   ui.FadeUp
+	pane     runner.Pane
 }
 
 func New(shared *state.Shared) Model {
+	targets := system.Targets(shared.Pkg, shared.Config.Cleanup)
 	return Model{
-		shared:  shared,
-		phase:   phasePackageCleanup,
-		cursor:  actionYes,
-		shimmer: ui.Shimmer{Text: "Clearing user caches..."},
+		shared:   shared,
+		targets:  targets,
+		selected: make([]bool, len(targets)),
+		ran:      make([]bool, len(targets)),
+		errs:     make([]error, len(targets)),
+		shimmer:  ui.Shimmer{Text: "Measuring disk usage..."},
+		pane:     runner.NewPane(shared),
 	}
 }
 
 func (m Model) Init() tea.Cmd {
-	cmd := system.CleanupCommand(m.shared.Distro.Type)
-	if cmd == nil {
-		return func() tea.Msg {
-			return app.ExecDoneMsg{Err: fmt.Errorf("unsupported distro: %s", m.shared.Distro.Type)}
+	// Also called when returning from the log viewer; never rerun.
+	if m.phase != phaseScan {
+		return nil
+	}
+	return tea.Batch(m.shimmer.Tick(), measure(m.targets, nil))
+}
+
+// measure sizes the targets in the background; only those in which
+// (when non-nil) are measured.
+func measure(targets []system.Target, which []bool) tea.Cmd {
+	return func() tea.Msg {
+		msg := measuredMsg{sizes: make([]int64, len(targets)), errs: make([]error, len(targets))}
+		for i, t := range targets {
+			if which == nil || which[i] {
+				msg.sizes[i], msg.errs[i] = t.Measure()
+			}
 		}
+		return msg
 	}
-	return tea.ExecProcess(cmd, func(err error) tea.Msg {
-		return app.ExecDoneMsg{Err: err}
-	})
 }
 
 func (m Model) Update(msg tea.Msg) (app.Screen, tea.Cmd) {
-	// Handle shimmer ticks during cache clearing.
-	if m.phase == phaseClearingCache {
+	pane, cmd, handled := m.pane.Update(msg)
+	m.pane = pane
+	if handled {
+		return m, cmd
+	}
+
+	switch m.phase {
+	case phaseScan, phaseClean, phaseRemeasure:
 		if cmd := (&m.shimmer).Update(msg); cmd != nil {
-			if done, ok := msg.(cacheClearDoneMsg); ok {
-				return m.finishCacheClean(done)
-			}
 			return m, cmd
 		}
-		if done, ok := msg.(cacheClearDoneMsg); ok {
-			return m.finishCacheClean(done)
-		}
-		return m, nil
-	}
-
-	// Handle fade-up ticks on done screen.
-	if m.phase == phaseDone {
+	case phaseDone:
 		if cmd := (&m.fadeup).Update(msg); cmd != nil {
 			return m, cmd
 		}
 	}
 
 	switch msg := msg.(type) {
-	case app.ExecDoneMsg:
-		m.pkgErr = msg.Err
-		m.phase = phaseAskUserCache
+	case measuredMsg:
+		if m.phase == phaseRemeasure {
+			m.after = msg.sizes
+			return m.finish()
+		}
+		m.before = msg.sizes
+		m.scanErrs = msg.errs
+		for i := range m.targets {
+			m.selected[i] = msg.errs[i] == nil && msg.sizes[i] > 0
+		}
+		m.phase = phaseSelect
 		return m, nil
 
-	case cacheClearDoneMsg:
-		return m.finishCacheClean(msg)
+	case app.ExecDoneMsg:
+		if m.phase != phaseClean {
+			return m, nil
+		}
+		if msg.Err != nil && m.logPath == "" {
+			m.logPath = m.pane.TranscriptPath()
+		}
+		return m.targetDone(msg.Err)
+
+	case clearedMsg:
+		return m.targetDone(msg.err)
 
 	case tea.KeyMsg:
 		switch m.phase {
-		case phaseAskUserCache:
-			switch msg.String() {
-			case "down", "up", "tab":
-				if m.cursor == actionYes {
-					m.cursor = actionNo
-				} else {
-					m.cursor = actionYes
-				}
-			case "enter", " ":
-				if m.cursor == actionYes {
-					m.phase = phaseClearingCache
-					return m, tea.Batch(m.shimmer.Tick(), m.clearCaches())
-				}
-				return m.skipCache()
-			case "y":
-				m.phase = phaseClearingCache
-				return m, tea.Batch(m.shimmer.Tick(), m.clearCaches())
-			case "n":
-				return m.skipCache()
-			}
+		case phaseSelect:
+			return m.updateSelect(msg)
 		case phaseDone:
+			if msg.String() == "l" && m.logPath != "" {
+				return m, app.Navigate(transcript.New(m.shared, m.logPath))
+			}
 			return m, app.PopScreen()
 		}
 	}
 	return m, nil
 }
 
-// finishCacheClean transitions to phaseDone after cache clearing completes.
-func (m Model) finishCacheClean(msg cacheClearDoneMsg) (app.Screen, tea.Cmd) {
-	m.cacheErr = msg.err
-	m.cacheCleared = true
-	m.phase = phaseDone
-	m.logAndNotify()
-	m.fadeup = buildFadeup(m.pkgErr, true, m.cacheErr == nil, m.cacheErr)
-	return m, tea.Batch(m.fadeup.Start(), m.toastCmd())
+func (m Model) updateSelect(msg tea.KeyMsg) (app.Screen, tea.Cmd) {
+	if len(m.targets) == 0 {
+		return m, nil
+	}
+	switch msg.String() {
+	case "down":
+		m.cursor++
+		if m.cursor >= len(m.targets) {
+			m.cursor = 0
+		}
+	case "up":
+		m.cursor--
+		if m.cursor < 0 {
+			m.cursor = len(m.targets) - 1
+		}
+	case " ":
+		m.selected[m.cursor] = !m.selected[m.cursor]
+	case "a":
+		all := m.selectedCount() < len(m.targets)
+		for i := range m.selected {
+			m.selected[i] = all
+		}
+	case "d":
+		var chosen []system.Target
+		for i, t := range m.targets {
+			if m.selected[i] {
+				chosen = append(chosen, t)
+			}
+		}
+		if len(chosen) == 0 {
+			return m, nil
+		}
+		return m, app.Navigate(NewDryRun(m.shared, chosen))
+	case "enter":
+		if m.selectedCount() == 0 {
+			return m, nil
+		}
+		m.phase = phaseClean
+		m.current = 0
+		return m.cleanNext()
+	}
+	return m, nil
 }
 
-// skipCache transitions to phaseDone without clearing caches.
-func (m Model) skipCache() (app.Screen, tea.Cmd) {
-	m.phase = phaseDone
-	m.logAndNotify()
-	m.fadeup = buildFadeup(m.pkgErr, false, false, nil)
-	return m, tea.Batch(m.fadeup.Start(), m.toastCmd())
+func (m Model) selectedCount() int {
+	n := 0
+	for _, s := range m.selected {
+		if s {
+			n++
+		}
+	}
+	return n
 }
 
-func (m Model) toastCmd() tea.Cmd {
-	if m.pkgErr == nil && m.cacheErr == nil {
-		return app.Toast("✓ System cleanup completed", false)
+// cleanNext starts the next selected target, or re-measures once all ran.
+func (m Model) cleanNext() (app.Screen, tea.Cmd) {
+	for ; m.current < len(m.targets); m.current++ {
+		if !m.selected[m.current] {
+			continue
+		}
+		t := m.targets[m.current]
+		m.ran[m.current] = true
+		if t.Command != nil {
+			if c := t.Command(); c != nil {
+				return m, m.pane.Exec(c, t.Name)
+			}
+			continue // Nothing to do (e.g. no orphans)
+		}
+		m.shimmer = ui.Shimmer{Text: "Clearing " + t.Name + "..."}
+		clearFn := t.Clear
+		return m, tea.Batch(m.shimmer.Tick(), func() tea.Msg { return clearedMsg{err: clearFn()} })
 	}
-	return nil
+	m.phase = phaseRemeasure
+	m.shimmer = ui.Shimmer{Text: "Measuring reclaimed space..."}
+	return m, tea.Batch(m.shimmer.Tick(), measure(m.targets, m.ran))
 }
 
-func buildFadeup(pkgErr error, cacheRan, cacheOK bool, cacheErr error) ui.FadeUp {
-	var lines []string
-	if pkgErr != nil {
-		lines = append(lines, theme.WarningStyle().Render("⚠  Package cleanup had issues"))
-	} else {
-		lines = append(lines, theme.SuccessStyle().Render("✓  Package cleanup"))
+func (m Model) targetDone(err error) (app.Screen, tea.Cmd) {
+	m.errs[m.current] = err
+	m.current++
+	return m.cleanNext()
+}
+
+// freed is the space reclaimed from target i.
+func (m Model) freed(i int) int64 {
+	if i >= len(m.after) || m.after[i] >= m.before[i] {
+		return 0
 	}
-	if cacheRan {
-		if cacheOK {
-			lines = append(lines, theme.SuccessStyle().Render("✓  User caches cleared"))
-		} else {
-			lines = append(lines, theme.WarningStyle().Render(fmt.Sprintf("⚠  User caches: %v", cacheErr)))
+	return m.before[i] - m.after[i]
+}
+
+// finish logs the result and shows the per-target report.
+func (m Model) finish() (app.Screen, tea.Cmd) {
+	m.phase = phaseDone
+	failed := false
+	var lines []string
+	for i, t := range m.targets {
+		if !m.ran[i] {
+			continue
+		}
+		if err := m.errs[i]; err != nil {
+			failed = true
+			lines = append(lines, theme.WarningStyle().Render(fmt.Sprintf("⚠  %s: %v", t.Name, err)))
+			continue
 		}
+		lines = append(lines, theme.SuccessStyle().Render(
+			fmt.Sprintf("✓  %-18s freed %s", t.Name, system.FormatSize(m.freed(i)))))
 	}
-	return ui.FadeUp{Lines: lines, Visible: 0}
-}
+	m.fadeup = ui.FadeUp{Lines: lines}
 
-func (m Model) clearCaches() tea.Cmd {
-	return func() tea.Msg {
-		return cacheClearDoneMsg{err: system.ClearUserCaches()}
+	summary := "Freed " + system.FormatSize(m.totalFreed())
+	if failed {
+		logging.LogAction("System cleanup completed with errors · " + summary) //nolint:errcheck
+	} else {
+		logging.LogAction("System cleanup completed · " + summary) //nolint:errcheck
 	}
+	system.Notify("mypctools", "System cleanup completed · "+summary)
+	return m, m.fadeup.Start()
 }
 
-func (m Model) logAndNotify() {
-	if m.pkgErr != nil {
-		logging.LogAction("System cleanup completed with errors")
-	} else {
-		logging.LogAction("System cleanup completed")
+func (m Model) totalFreed() int64 {
+	var total int64
+	for i := range m.targets {
+		if m.ran[i] {
+			total += m.freed(i)
+		}
 	}
-	system.Notify("mypctools", "System cleanup completed")
+	return total
 }
 
 func (m Model) View() string {
@@ -194,64 +289,92 @@ func (m Model) View() string {
 	muted := theme.MutedStyle()
 
 	switch m.phase {
-	case phasePackageCleanup:
-		return center(muted.Render("Running package cleanup..."))
-
-	case phaseAskUserCache:
-		var pkgStatus string
-		if m.pkgErr != nil {
-			pkgStatus = theme.WarningStyle().Render("⚠  Package cleanup had issues")
-		} else {
-			pkgStatus = theme.SuccessStyle().Render("✓  Package cleanup")
-		}
-		sep := "   " + theme.HelpDividerStyle().Render(strings.Repeat("─", 36))
-		question := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#ffffff")).Render("Clear user caches?")
-		hint := muted.Render("thumbnails, trash, temp files")
+	case phaseScan, phaseRemeasure:
+		return center(m.shimmer.View())
 
-		items := []ui.ListItem{
-			{Icon: "✓", Label: "Yes, clear"},
-			{Icon: "—", Label: "Skip"},
-		}
-		cursor := 0
-		if m.cursor == actionNo {
-			cursor = 1
+	case phaseSelect:
+		return m.viewSelect(center)
+
+	case phaseClean:
+		if m.pane.Running() {
+			return m.pane.View("Cleaning " + m.targets[m.current].Name)
 		}
-		menu := ui.RenderList(items, cursor, ui.ListConfig{Width: 40, MaxInnerWidth: 40})
-
-		return lipgloss.JoinVertical(lipgloss.Left,
-			center(pkgStatus),
-			sep,
-			"",
-			center(question),
-			center(hint),
-			"",
-			lipgloss.NewStyle().Width(width).Align(lipgloss.Center).Render(menu),
-		)
-
-	case phaseClearingCache:
 		return center(m.shimmer.View())
 
 	case phaseDone:
 		title := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#ffffff")).Render("Cleanup Complete")
-		prompt := muted.Render("press any key to continue")
-		parts := []string{center(title), ""}
+		total := theme.SuccessStyle().Render("Freed " + system.FormatSize(m.totalFreed()))
+		promptText := "press any key to continue"
+		if m.logPath != "" {
+			promptText = "press l to view the output log, any other key to continue"
+		}
+		parts := []string{center(title), center(total), ""}
 		for _, l := range m.fadeup.VisibleLines() {
 			parts = append(parts, "   "+l)
 		}
-		parts = append(parts, "", center(prompt))
+		parts = append(parts, "", center(muted.Render(promptText)))
 		return lipgloss.JoinVertical(lipgloss.Left, parts...)
 	}
 
 	return ""
 }
 
+func (m Model) viewSelect(center func(string) string) string {
+	if len(m.targets) == 0 {
+		return center(theme.MutedStyle().Render("Nothing to clean on this system"))
+	}
+
+	var reclaimable int64
+	items := make([]ui.ListItem, len(m.targets))
+	for i, t := range m.targets {
+		size := theme.MutedStyle().Render(system.FormatSize(m.before[i]))
+		if m.scanErrs[i] != nil {
+			size = theme.WarningStyle().Render("size unknown")
+		}
+		icon := "◇"
+		if m.selected[i] {
+			icon = "◆"
+			reclaimable += m.before[i]
+		}
+		items[i] = ui.ListItem{
+			Icon:        icon,
+			Label:       t.Name,
+			Suffix:      size,
+			Description: t.Description,
+			Dimmed:      !m.selected[i],
+		}
+	}
+
+	heading := lipgloss.NewStyle().Foreground(lipgloss.Color(theme.Current.Primary)).
+		Render(fmt.Sprintf("%s reclaimable from %d selected", system.FormatSize(reclaimable), m.selectedCount()))
+	height := (m.shared.ContentHeight - 4) / 2 // Two lines per item
+	list := ui.RenderList(items, m.cursor, ui.ListConfig{
+		Width:         64,
+		MaxInnerWidth: 64,
+		Height:        max(height, 2),
+	})
+	return lipgloss.JoinVertical(lipgloss.Left, center(heading), "", center(list))
+}
+
 func (m Model) Title() string { return "System Cleanup" }
 
-func (m Model) HandlesBack() bool { return false }
+func (m Model) HandlesBack() bool { return m.pane.Running() }
+
+// HandlesInterrupt passes ctrl+c to the running command.
+func (m Model) HandlesInterrupt() bool { return m.pane.Running() }
 
 func (m Model) ShortHelp() []string {
-	if m.phase == phaseAskUserCache {
-		return []string{"y yes", "n no"}
+	if m.pane.Running() {
+		return m.pane.ShortHelp()
+	}
+	switch m.phase {
+	case phaseSelect:
+		return []string{"space toggle", "a all", "d dry run", "enter clean", "esc cancel"}
+	case phaseDone:
+		if m.logPath != "" {
+			return []string{"l view log", "any key continue"}
+		}
+		return []string{"any key continue"}
 	}
 	return []string{}
 }
@@ -0,0 +1,147 @@
+package cleanup
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+	"github.com/reisset/mypctools/tui/internal/app"
+	"github.com/reisset/mypctools/tui/internal/state"
+	"github.com/reisset/mypctools/tui/internal/system"
+	"github.com/reisset/mypctools/tui/internal/theme"
+)
+
+// planLine is one line of the dry-run report; headers name a target.
+type planLine struct {
+	text   string
+	header bool
+	warn   bool
+}
+
+type planLoadedMsg struct{ lines []planLine }
+
+// DryRunModel lists what cleaning the selected targets would delete.
+type DryRunModel struct {
+	shared   *state.Shared
+	targets  []system.Target
+	lines    []planLine
+	loaded   bool
+	viewport viewport.Model
+}
+
+// NewDryRun creates a dry-run viewer for targets.
+func NewDryRun(shared *state.Shared, targets []system.Target) DryRunModel {
+	m := DryRunModel{shared: shared, targets: targets}
+	m.viewport = viewport.New(m.size())
+	return m
+}
+
+func (m DryRunModel) Init() tea.Cmd {
+	if m.loaded {
+		return nil
+	}
+	targets := m.targets
+	return func() tea.Msg {
+		var lines []planLine
+		for _, t := range targets {
+			lines = append(lines, planLine{text: t.Name, header: true})
+			plan, err := t.DryRun()
+			for _, l := range plan {
+				lines = append(lines, planLine{text: "  " + l})
+			}
+			switch {
+			case err != nil:
+				lines = append(lines, planLine{text: "  " + err.Error(), warn: true})
+			case len(plan) == 0:
+				lines = append(lines, planLine{text: "  nothing to remove"})
+			}
+		}
+		return planLoadedMsg{lines: lines}
+	}
+}
+
+func (m DryRunModel) Update(msg tea.Msg) (app.Screen, tea.Cmd) {
+	switch msg := msg.(type) {
+	case planLoadedMsg:
+		m.loaded = true
+		m.lines = msg.lines
+		m.refresh()
+	case tea.WindowSizeMsg:
+		m.viewport.Width, m.viewport.Height = m.size()
+		m.refresh()
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "up", "k":
+			m.viewport.LineUp(1)
+		case "down", "j":
+			m.viewport.LineDown(1)
+		case "pgup":
+			m.viewport.HalfViewUp()
+		case "pgdown", " ":
+			m.viewport.HalfViewDown()
+		case "home":
+			m.viewport.GotoTop()
+		case "end":
+			m.viewport.GotoBottom()
+		}
+	}
+	return m, nil
+}
+
+func (m *DryRunModel) refresh() {
+	out := make([]string, len(m.lines))
+	for i, l := range m.lines {
+		text := runewidth.Truncate(l.text, m.viewport.Width, "…")
+		switch {
+		case l.header:
+			out[i] = lipgloss.NewStyle().Foreground(lipgloss.Color(theme.Current.Primary)).Bold(true).Render(text)
+		case l.warn:
+			out[i] = theme.WarningStyle().Render(text)
+		default:
+			out[i] = text
+		}
+	}
+	m.viewport.SetContent(strings.Join(out, "\n"))
+}
+
+func (m DryRunModel) size() (int, int) {
+	w := m.shared.TerminalWidth - 4
+	if w < 20 {
+		w = 76
+	}
+	h := m.shared.ContentHeight - 2
+	if h < 5 {
+		h = 5
+	}
+	return w, h
+}
+
+func (m DryRunModel) View() string {
+	width := m.shared.TerminalWidth
+	if width == 0 {
+		width = 80
+	}
+	center := func(s string) string {
+		return lipgloss.NewStyle().Width(width).Align(lipgloss.Center).Render(s)
+	}
+
+	if !m.loaded {
+		return center(theme.MutedStyle().Render("Planning..."))
+	}
+	status := theme.MutedStyle().Render(fmt.Sprintf("Dry run — nothing is deleted  ·  %d targets", len(m.targets)))
+	return lipgloss.JoinVertical(lipgloss.Left,
+		center(status),
+		lipgloss.NewStyle().PaddingLeft(2).Render(m.viewport.View()),
+	)
+}
+
+func (m DryRunModel) Title() string { return "Cleanup Dry Run" }
+
+func (m DryRunModel) HandlesBack() bool { return false }
+
+func (m DryRunModel) ShortHelp() []string {
+	return []string{"↑↓ scroll", "pgup/pgdn page", "home/end jump"}
+}
@@ -1,123 +1,522 @@
 package exec
 
 import (
+	"errors"
 	"fmt"
 	"os/exec"
-	"path/filepath"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/reisset/mypctools/tui/internal/app"
+	"github.com/reisset/mypctools/tui/internal/backup"
 	"github.com/reisset/mypctools/tui/internal/bundle"
 	"github.com/reisset/mypctools/tui/internal/logging"
+	"github.com/reisset/mypctools/tui/internal/rollback"
+	"github.com/reisset/mypctools/tui/internal/runner"
+	"github.com/reisset/mypctools/tui/internal/screen/transcript"
 	"github.com/reisset/mypctools/tui/internal/state"
 	"github.com/reisset/mypctools/tui/internal/system"
 	"github.com/reisset/mypctools/tui/internal/theme"
+	"github.com/reisset/mypctools/tui/internal/ui"
 )
 
+// outcome is the result of running one bundle in the queue.
+type outcome int
+
+const (
+	outcomePending outcome = iota
+	outcomeOK
+	outcomeFailed
+	outcomeSkipped // Not run: an earlier failure stopped the batch, or a dependency failed
+)
+
+// rollbackPhase tracks undoing failed installs from the failure screen.
+type rollbackPhase int
+
+const (
+	rollbackNone    rollbackPhase = iota
+	rollbackRunning               // Restoring snapshots
+	rollbackShell                 // Switching the login shell back in the pane
+	rollbackDone
+)
+
+// snapshotMsg carries the pre-install snapshot for queue[index].
+type snapshotMsg struct {
+	index int
+	snap  *rollback.Snapshot
+	err   error
+}
+
+// rollbackDoneMsg reports the result of restoring snapshots.
+type rollbackDoneMsg struct {
+	restored int
+	shell    string // Login shell still to restore
+	err      error
+}
+
 // Model handles script execution with full terminal control.
+// It runs the same action for one or more bundles in order.
 type Model struct {
-	shared *state.Shared
-	bundle bundle.Bundle
-	action string // "install" or "uninstall"
-	done   bool
-	err    error
+	shared          *state.Shared
+	queue           []bundle.Bundle
+	outcomes        []outcome
+	errs            []error
+	logs            []string             // Transcript per bundle ("" when output was not captured)
+	snaps           []*rollback.Snapshot // Pre-install state per bundle (install only)
+	index           int                  // Position in queue of the bundle currently running
+	action          string               // "install" or "uninstall"
+	continueOnError bool                 // Keep going after a failure instead of stopping
+	summary         bool                 // Show a per-bundle summary when finished
+	done            bool
+	err             error
+	fadeup          ui.FadeUp
+	pane            runner.Pane
+	rollback        rollbackPhase
+	rollbackResult  string // Outcome line shown after a rollback
+	rollbackErr     error
 }
 
 // New creates an exec screen for the given bundle and action.
 func New(shared *state.Shared, b bundle.Bundle, action string) Model {
+	return NewSequence(shared, []bundle.Bundle{b}, action)
+}
+
+// NewSequence creates an exec screen that runs action for each bundle in
+// order (e.g. dependencies before the bundle that requires them), stopping
+// at the first failure.
+func NewSequence(shared *state.Shared, bundles []bundle.Bundle, action string) Model {
 	return Model{
-		shared: shared,
-		bundle: b,
-		action: action,
+		shared:   shared,
+		queue:    bundles,
+		outcomes: make([]outcome, len(bundles)),
+		errs:     make([]error, len(bundles)),
+		logs:     make([]string, len(bundles)),
+		snaps:    make([]*rollback.Snapshot, len(bundles)),
+		action:   action,
+		pane:     runner.NewPane(shared),
+	}
+}
+
+// NewBatch is like NewSequence but optionally continues past failures and
+// always finishes on a summary of successes and failures.
+func NewBatch(shared *state.Shared, bundles []bundle.Bundle, action string, continueOnError bool) Model {
+	m := NewSequence(shared, bundles, action)
+	m.continueOnError = continueOnError
+	m.summary = true
+	return m
+}
+
+// current returns the bundle being run.
+func (m Model) current() bundle.Bundle {
+	if m.index < len(m.queue) {
+		return m.queue[m.index]
 	}
+	return bundle.Bundle{}
 }
 
 func (m Model) Init() tea.Cmd {
-	// Build script path — validate inputs to prevent path traversal.
-	if strings.Contains(m.bundle.ID, "/") || strings.Contains(m.bundle.ID, "\\") {
-		return func() tea.Msg {
-			return app.ExecDoneMsg{Err: fmt.Errorf("invalid bundle ID: %s", m.bundle.ID)}
-		}
+	// Also called when returning from the log viewer; never rerun.
+	if m.done || len(m.queue) == 0 {
+		return nil
+	}
+	return m.runCurrent()
+}
+
+// runCurrent starts the current bundle. Installs first snapshot the paths
+// the bundle touches so a failure can be rolled back.
+func (m Model) runCurrent() tea.Cmd {
+	if m.action != "install" {
+		return m.startScript()
 	}
-	if m.action != "install" && m.action != "uninstall" {
+	index, b := m.index, m.current()
+	return func() tea.Msg {
+		snap, err := rollback.Take(b)
+		return snapshotMsg{index: index, snap: snap, err: err}
+	}
+}
+
+func (m Model) startScript() tea.Cmd {
+	// Build script path — validate inputs to prevent path traversal.
+	scriptPath, err := bundle.ScriptPath(m.shared.RootDir, m.current().ID, m.action)
+	if err != nil {
 		return func() tea.Msg {
-			return app.ExecDoneMsg{Err: fmt.Errorf("invalid action: %s", m.action)}
+			return app.ExecDoneMsg{Err: err}
 		}
 	}
-	scriptPath := filepath.Join(m.shared.RootDir, "scripts", m.bundle.ID, m.action+".sh")
 
-	// Use tea.ExecProcess to give the script full terminal control
+	// The pane either captures output or gives the script full terminal control
 	cmd := exec.Command("bash", scriptPath)
-	return tea.ExecProcess(cmd, func(err error) tea.Msg {
-		return app.ExecDoneMsg{Err: err}
-	})
+	return m.pane.Exec(cmd, fmt.Sprintf("%s %s", m.action, m.current().ID))
 }
 
 func (m Model) Update(msg tea.Msg) (app.Screen, tea.Cmd) {
+	pane, cmd, handled := m.pane.Update(msg)
+	m.pane = pane
+	if handled {
+		return m, cmd
+	}
+
+	// Handle fade-up ticks on the summary.
+	if m.done && m.summary {
+		if cmd := (&m.fadeup).Update(msg); cmd != nil {
+			return m, cmd
+		}
+	}
+
 	switch msg := msg.(type) {
+	case snapshotMsg:
+		if msg.err != nil {
+			// Not fatal: the install still runs, it just can't be rolled back
+			logging.LogAction(fmt.Sprintf("Snapshot for %s failed: %v", m.current().Name, msg.err)) //nolint:errcheck
+		}
+		m.snaps[msg.index] = msg.snap
+		return m, m.startScript()
+
+	case rollbackDoneMsg:
+		m.rollbackErr = msg.err
+		m.rollbackResult = fmt.Sprintf("Rolled back %d paths — installed packages were kept", msg.restored)
+		logging.LogAction(fmt.Sprintf("Rolled back failed %s: %d paths restored", m.action, msg.restored)) //nolint:errcheck
+		if msg.shell != "" {
+			m.rollback = rollbackShell
+			return m, m.pane.Exec(rollback.ShellCommand(msg.shell), "restore login shell")
+		}
+		m.rollback = rollbackDone
+		return m, nil
+
 	case app.ExecDoneMsg:
+		if m.rollback == rollbackShell {
+			m.rollback = rollbackDone
+			if msg.Err != nil && m.rollbackErr == nil {
+				m.rollbackErr = fmt.Errorf("login shell not restored: %w", msg.Err)
+			}
+			return m, nil
+		}
+		if m.done {
+			return m, nil
+		}
+		b := m.current()
+		m.errs[m.index] = msg.Err
+		m.logs[m.index] = m.pane.TranscriptPath()
 		if msg.Err != nil {
-			m.done = true
+			m.outcomes[m.index] = outcomeFailed
 			m.err = msg.Err
-			logging.LogAction(fmt.Sprintf("Script %s %s failed", m.bundle.Name, m.action)) //nolint:errcheck
-			return m, nil
+			logging.LogAction(fmt.Sprintf("Script %s %s failed", b.Name, m.action)) //nolint:errcheck
+			if !m.continueOnError {
+				for i := m.index + 1; i < len(m.queue); i++ {
+					m.outcomes[i] = outcomeSkipped
+				}
+				return m.finish()
+			}
+		} else {
+			m.outcomes[m.index] = outcomeOK
+			logging.LogAction(fmt.Sprintf("Script %s %s completed", b.Name, m.action)) //nolint:errcheck
+			m.snaps[m.index].Discard()                                                 //nolint:errcheck
+			m.snaps[m.index] = nil
 		}
-		logging.LogAction(fmt.Sprintf("Script %s %s completed", m.bundle.Name, m.action)) //nolint:errcheck
-		system.Notify("mypctools", fmt.Sprintf("%s %s completed", m.bundle.Name, m.action))
-		icons := theme.GetIcons()
-		return m, app.Toast(
-			fmt.Sprintf("%s %s %s completed", icons.Check, m.bundle.Name, m.action),
-			false,
-		)
+		if m.advance() {
+			return m, m.runCurrent()
+		}
+		return m.finish()
 
 	case tea.KeyMsg:
 		if m.done {
-			// Any key returns (only reached on error)
+			switch msg.String() {
+			case "l":
+				if path := m.failedLog(); path != "" {
+					return m, app.Navigate(transcript.New(m.shared, path))
+				}
+			case "r":
+				if m.canRollBack() {
+					m.rollback = rollbackRunning
+					return m, m.rollBack()
+				}
+			}
+			if m.rollback == rollbackRunning || m.rollback == rollbackShell {
+				return m, nil
+			}
+			// Any other key returns (reached on error or summary)
+			m.discardSnapshots()
 			return m, app.PopScreen()
 		}
 	}
 	return m, nil
 }
 
+// canRollBack reports whether a failed install has a snapshot to restore.
+func (m Model) canRollBack() bool {
+	if m.rollback != rollbackNone {
+		return false
+	}
+	for i, o := range m.outcomes {
+		if o == outcomeFailed && m.snaps[i] != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// rollBack restores the snapshots of failed installs, last first.
+// Bundles that installed successfully are kept.
+func (m Model) rollBack() tea.Cmd {
+	var snaps []*rollback.Snapshot
+	for i := len(m.queue) - 1; i >= 0; i-- {
+		if m.outcomes[i] == outcomeFailed && m.snaps[i] != nil {
+			snaps = append(snaps, m.snaps[i])
+		}
+	}
+	return func() tea.Msg {
+		var done rollbackDoneMsg
+		var errs []error
+		for _, s := range snaps {
+			res, err := s.Restore()
+			done.restored += res.Restored
+			if err != nil {
+				errs = append(errs, err)
+			}
+			if res.Shell != "" && done.shell == "" {
+				done.shell = res.Shell
+			}
+		}
+		done.err = errors.Join(errs...)
+		return done
+	}
+}
+
+// discardSnapshots deletes snapshots that were not needed.
+func (m Model) discardSnapshots() {
+	for _, s := range m.snaps {
+		s.Discard() //nolint:errcheck
+	}
+}
+
+// advance moves index to the next runnable bundle, skipping any whose
+// dependencies failed. Returns false when the queue is exhausted.
+func (m *Model) advance() bool {
+	for m.index++; m.index < len(m.queue); m.index++ {
+		if !m.dependencyFailed(m.queue[m.index]) {
+			return true
+		}
+		m.outcomes[m.index] = outcomeSkipped
+	}
+	return false
+}
+
+func (m Model) dependencyFailed(b bundle.Bundle) bool {
+	for _, dep := range b.Requires {
+		for i, q := range m.queue {
+			if q.ID == dep && (m.outcomes[i] == outcomeFailed || m.outcomes[i] == outcomeSkipped) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// failedLog returns the transcript of the first failed bundle, if captured.
+func (m Model) failedLog() string {
+	for i, o := range m.outcomes {
+		if o == outcomeFailed {
+			return m.logs[i]
+		}
+	}
+	return ""
+}
+
+// finish ends the run: a toast on full success, otherwise the error screen
+// or, for batches, the summary.
+func (m Model) finish() (app.Screen, tea.Cmd) {
+	var ok, failed []bundle.Bundle
+	for i, b := range m.queue {
+		switch m.outcomes[i] {
+		case outcomeOK:
+			ok = append(ok, b)
+		case outcomeFailed:
+			failed = append(failed, b)
+		}
+	}
+
+	if m.summary {
+		m.done = true
+		m.fadeup = buildSummary(m.queue, m.outcomes, m.errs)
+		system.Notify("mypctools", fmt.Sprintf("%s: %d ok, %d failed", m.action, len(ok), len(failed)))
+		return m, m.fadeup.Start()
+	}
+
+	if len(failed) > 0 {
+		m.done = true
+		return m, nil
+	}
+
+	names := bundle.Names(ok)
+	system.Notify("mypctools", fmt.Sprintf("%s %s completed", names, m.action))
+	icons := theme.GetIcons()
+	return m, app.Toast(
+		fmt.Sprintf("%s %s %s completed%s", icons.Check, names, m.action, backupHint(m.action, ok)),
+		false,
+	)
+}
+
+// backupHint reminds the user, after an uninstall, that files the installer
+// displaced can be restored from the Backups screen.
+func backupHint(action string, bundles []bundle.Bundle) string {
+	if action != "uninstall" {
+		return ""
+	}
+	count := 0
+	for _, b := range bundles {
+		saved, _ := backup.ForBundle(b.ID)
+		count += len(saved)
+	}
+	switch count {
+	case 0:
+		return ""
+	case 1:
+		return " · 1 backup can be restored"
+	}
+	return fmt.Sprintf(" · %d backups can be restored", count)
+}
+
+func buildSummary(queue []bundle.Bundle, outcomes []outcome, errs []error) ui.FadeUp {
+	lines := make([]string, 0, len(queue))
+	for i, b := range queue {
+		switch outcomes[i] {
+		case outcomeOK:
+			lines = append(lines, theme.SuccessStyle().Render("✓  "+b.Name))
+		case outcomeFailed:
+			lines = append(lines, theme.ErrorStyle().Render(fmt.Sprintf("✕  %s: %v", b.Name, errs[i])))
+		default:
+			lines = append(lines, theme.MutedStyle().Render("—  "+b.Name+" (skipped)"))
+		}
+	}
+	return ui.FadeUp{Lines: lines, Visible: 0}
+}
+
 func (m Model) View() string {
 	width := m.shared.TerminalWidth
 	if width == 0 {
 		width = 80
 	}
 
+	center := func(s string) string {
+		return lipgloss.NewStyle().Width(width).Align(lipgloss.Center).Render(s)
+	}
+
+	if m.rollback == rollbackShell && m.pane.Running() {
+		return m.pane.View("Restoring login shell")
+	}
+
+	if m.done && m.summary {
+		okCount, failCount := 0, 0
+		for _, o := range m.outcomes {
+			switch o {
+			case outcomeOK:
+				okCount++
+			case outcomeFailed:
+				failCount++
+			}
+		}
+		title := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#ffffff")).
+			Render(fmt.Sprintf("%d succeeded · %d failed", okCount, failCount))
+		parts := []string{center(title), ""}
+		for _, l := range m.fadeup.VisibleLines() {
+			parts = append(parts, "   "+l)
+		}
+		parts = append(parts, "")
+		if line := m.rollbackLine(); line != "" {
+			parts = append(parts, center(line))
+		}
+		parts = append(parts, center(theme.MutedStyle().Render(m.continuePrompt())))
+		return lipgloss.JoinVertical(lipgloss.Left, parts...)
+	}
+
 	var content string
 
 	if m.done {
 		// Only reached on error (success pops via toast)
 		statusLine := theme.ErrorStyle().Render(fmt.Sprintf("Failed: %v", m.err))
-		prompt := theme.MutedStyle().Render("Press any key to continue...")
-		content = lipgloss.JoinVertical(lipgloss.Center,
-			"",
-			statusLine,
-			"",
-			prompt,
-		)
+		if len(m.queue) > 1 {
+			statusLine = theme.ErrorStyle().Render(fmt.Sprintf("%s failed: %v", m.current().Name, m.err))
+		}
+		lines := []string{"", statusLine, ""}
+		if line := m.rollbackLine(); line != "" {
+			lines = append(lines, line)
+		}
+		lines = append(lines, theme.MutedStyle().Render(m.continuePrompt()))
+		content = lipgloss.JoinVertical(lipgloss.Center, lines...)
+	} else if m.pane.Running() {
+		return m.pane.View(fmt.Sprintf("Running %s for %s", m.action, m.current().Name))
 	} else {
-		content = theme.MutedStyle().Render(fmt.Sprintf("Running %s for %s...", m.action, m.bundle.Name))
+		content = theme.MutedStyle().Render(fmt.Sprintf("Running %s for %s...", m.action, m.current().Name))
 	}
 
-	return lipgloss.NewStyle().
-		Width(width).
-		Align(lipgloss.Center).
-		Render(content)
+	return center(content)
+}
+
+// rollbackLine reports rollback progress or its outcome.
+func (m Model) rollbackLine() string {
+	switch m.rollback {
+	case rollbackRunning:
+		return theme.MutedStyle().Render("Rolling back...")
+	case rollbackDone:
+		if m.rollbackErr != nil {
+			return theme.ErrorStyle().Render(m.rollbackResult + " · " + m.rollbackErr.Error())
+		}
+		icons := theme.GetIcons()
+		return theme.SuccessStyle().Render(icons.Check + " " + m.rollbackResult)
+	}
+	return ""
+}
+
+func (m Model) continuePrompt() string {
+	if m.rollback == rollbackRunning {
+		return ""
+	}
+	var keys []string
+	if m.canRollBack() {
+		keys = append(keys, "r to roll back")
+	}
+	if m.failedLog() != "" {
+		keys = append(keys, "l to view the output log")
+	}
+	if len(keys) == 0 {
+		return "press any key to continue"
+	}
+	return "press " + strings.Join(keys, ", ") + ", any other key to continue"
 }
 
 func (m Model) Title() string {
-	return fmt.Sprintf("%s %s", m.action, m.bundle.Name)
+	if m.done && m.summary {
+		return fmt.Sprintf("%s summary", m.action)
+	}
+	if len(m.queue) > 1 {
+		return fmt.Sprintf("%s %s (%d/%d)", m.action, m.current().Name, m.index+1, len(m.queue))
+	}
+	return fmt.Sprintf("%s %s", m.action, m.current().Name)
+}
+
+// HandlesBack is true once done too, so esc discards unneeded snapshots on
+// the way out like any other key.
+func (m Model) HandlesBack() bool {
+	return m.done || m.pane.Running() || m.rollback == rollbackRunning
 }
 
-func (m Model) HandlesBack() bool { return false }
+// HandlesInterrupt passes ctrl+c to the running command.
+func (m Model) HandlesInterrupt() bool { return m.pane.Running() }
 
 func (m Model) ShortHelp() []string {
+	if m.pane.Running() {
+		return m.pane.ShortHelp()
+	}
 	if m.done {
-		return []string{"any key continue"}
+		var help []string
+		if m.canRollBack() {
+			help = append(help, "r roll back")
+		}
+		if m.failedLog() != "" {
+			help = append(help, "l view log")
+		}
+		return append(help, "any key continue")
 	}
 	return []string{}
 }
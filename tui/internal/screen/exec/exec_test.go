@@ -0,0 +1,45 @@
+package exec
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/reisset/mypctools/tui/internal/app"
+	"github.com/reisset/mypctools/tui/internal/bundle"
+	"github.com/reisset/mypctools/tui/internal/logging"
+	"github.com/reisset/mypctools/tui/internal/rollback"
+	"github.com/reisset/mypctools/tui/internal/state"
+)
+
+func TestEscDiscardsSnapshotsWhenDone(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	b := bundle.Bundle{ID: "kitty", Name: "Kitty"}
+	snap, err := rollback.Take(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	snapshots := filepath.Join(logging.DataDir(), "rollback")
+	if entries, _ := os.ReadDir(snapshots); len(entries) != 1 {
+		t.Fatalf("snapshot not taken: %v", entries)
+	}
+
+	m := New(&state.Shared{TerminalWidth: 80, ContentHeight: 20}, b, "install")
+	m.snaps[0] = snap
+	m.outcomes[0] = outcomeFailed
+	m.err = errors.New("exit status 1")
+	m.done = true
+	if !m.HandlesBack() {
+		t.Fatal("esc would skip the screen once done")
+	}
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if cmd == nil || cmd() != (app.PopScreenMsg{}) {
+		t.Error("esc did not leave the screen")
+	}
+	if entries, _ := os.ReadDir(snapshots); len(entries) != 0 {
+		t.Errorf("snapshot kept after leaving: %v", entries)
+	}
+}
@@ -0,0 +1,226 @@
+package preview
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/reisset/mypctools/tui/internal/app"
+	"github.com/reisset/mypctools/tui/internal/bundle"
+	"github.com/reisset/mypctools/tui/internal/state"
+	"github.com/reisset/mypctools/tui/internal/theme"
+	"github.com/reisset/mypctools/tui/internal/ui"
+)
+
+type previewDoneMsg struct {
+	result bundle.PreviewResult
+	err    error
+}
+
+// kindGroup is how one kind of planned change is labelled in the list.
+type kindGroup struct {
+	kind  string
+	title string
+	icon  string
+	warn  bool // Destructive or easily overlooked: render in the warning color
+}
+
+// groups lists change kinds in display order.
+var groups = []kindGroup{
+	{kind: "package", title: "Packages", icon: "+"},
+	{kind: "download", title: "Downloads", icon: "↓"},
+	{kind: "mkdir", title: "Directories", icon: "▸"},
+	{kind: "link", title: "Symlinks", icon: "→"},
+	{kind: "backup", title: "Backups", icon: "⚠", warn: true},
+	{kind: "write", title: "Files written", icon: "✎"},
+	{kind: "append", title: "Files appended to", icon: "✎"},
+	{kind: "remove", title: "Removed", icon: "✕", warn: true},
+	{kind: "setting", title: "Settings", icon: "⚙"},
+	{kind: "shell", title: "Default shell", icon: "$", warn: true},
+	{kind: "run", title: "Commands", icon: "$"},
+}
+
+// maxOutputLines caps how much script output is shown when a dry run fails.
+const maxOutputLines = 8
+
+// Model runs a bundle's install.sh in dry-run mode and lists what it would change.
+type Model struct {
+	shared   *state.Shared
+	bundle   bundle.Bundle
+	loaded   bool
+	result   bundle.PreviewResult
+	err      error
+	shimmer  ui.Shimmer
+	viewport viewport.Model
+}
+
+func New(shared *state.Shared, b bundle.Bundle) Model {
+	m := Model{
+		shared:  shared,
+		bundle:  b,
+		shimmer: ui.Shimmer{Text: "Running install.sh in dry-run mode..."},
+	}
+	m.viewport = viewport.New(m.size())
+	return m
+}
+
+func (m Model) Init() tea.Cmd {
+	if m.loaded {
+		return nil
+	}
+	rootDir, b := m.shared.RootDir, m.bundle
+	return tea.Batch(m.shimmer.Tick(), func() tea.Msg {
+		result, err := bundle.Preview(rootDir, b)
+		return previewDoneMsg{result: result, err: err}
+	})
+}
+
+func (m Model) Update(msg tea.Msg) (app.Screen, tea.Cmd) {
+	if !m.loaded {
+		if cmd := (&m.shimmer).Update(msg); cmd != nil {
+			return m, cmd
+		}
+	}
+
+	switch msg := msg.(type) {
+	case previewDoneMsg:
+		m.loaded = true
+		m.result = msg.result
+		m.err = msg.err
+		m.viewport.SetContent(m.renderChanges())
+	case tea.WindowSizeMsg:
+		m.viewport.Width, m.viewport.Height = m.size()
+		if m.loaded {
+			m.viewport.SetContent(m.renderChanges())
+		}
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "up", "k":
+			m.viewport.LineUp(1)
+		case "down", "j":
+			m.viewport.LineDown(1)
+		case "pgup":
+			m.viewport.HalfViewUp()
+		case "pgdown", " ":
+			m.viewport.HalfViewDown()
+		case "home":
+			m.viewport.GotoTop()
+		case "end":
+			m.viewport.GotoBottom()
+		}
+	}
+	return m, nil
+}
+
+func (m Model) size() (int, int) {
+	w := m.shared.TerminalWidth - 8
+	if w < 20 || w > 96 {
+		w = 72
+	}
+	h := m.shared.ContentHeight - 4
+	if h < 5 {
+		h = 5
+	}
+	return w, h
+}
+
+// renderChanges groups the planned changes by kind.
+func (m Model) renderChanges() string {
+	byKind := make(map[string][]string)
+	for _, c := range m.result.Changes {
+		byKind[c.Kind] = append(byKind[c.Kind], c.Detail)
+	}
+
+	header := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(theme.Current.Primary))
+	clip := lipgloss.NewStyle().MaxWidth(m.viewport.Width) // Long paths would wrap and break scrolling
+	var lines []string
+	render := func(g kindGroup, details []string) {
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, header.Render(fmt.Sprintf("%s (%d)", g.title, len(details))))
+		style := theme.MutedStyle()
+		if g.warn {
+			style = theme.WarningStyle()
+		}
+		for _, d := range details {
+			lines = append(lines, clip.Render("  "+style.Render(g.icon)+"  "+d))
+		}
+	}
+
+	known := make(map[string]bool, len(groups))
+	for _, g := range groups {
+		known[g.kind] = true
+		if details := byKind[g.kind]; len(details) > 0 {
+			render(g, details)
+		}
+	}
+	// Kinds added to lib/dryrun.sh later still show up, in script order.
+	for _, c := range m.result.Changes {
+		if !known[c.Kind] {
+			known[c.Kind] = true
+			render(kindGroup{kind: c.Kind, title: c.Kind, icon: "•"}, byKind[c.Kind])
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (m Model) View() string {
+	width := m.shared.TerminalWidth
+	if width == 0 {
+		width = 80
+	}
+	center := func(s string) string {
+		return lipgloss.NewStyle().Width(width).Align(lipgloss.Center).Render(s)
+	}
+
+	if !m.loaded {
+		return center(m.shimmer.View())
+	}
+
+	if m.err != nil {
+		parts := []string{center(theme.ErrorStyle().Render(m.err.Error()))}
+		if out := tailLines(m.result.Output, maxOutputLines); len(out) > 0 {
+			parts = append(parts, "")
+			for _, l := range out {
+				parts = append(parts, "   "+theme.MutedStyle().Render(l))
+			}
+		}
+		return lipgloss.JoinVertical(lipgloss.Left, parts...)
+	}
+
+	if len(m.result.Changes) == 0 {
+		icons := theme.GetIcons()
+		return center(theme.SuccessStyle().Render(icons.Check + " Nothing to change — already set up"))
+	}
+
+	summary := theme.MutedStyle().Render(fmt.Sprintf("%d planned changes · nothing has been modified", len(m.result.Changes)))
+	return lipgloss.JoinVertical(lipgloss.Left, center(summary), "", center(m.viewport.View()))
+}
+
+// tailLines returns the last n non-empty lines of s.
+func tailLines(s string, n int) []string {
+	var lines []string
+	for _, l := range strings.Split(strings.TrimSpace(s), "\n") {
+		if strings.TrimSpace(l) != "" {
+			lines = append(lines, l)
+		}
+	}
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return lines
+}
+
+func (m Model) Title() string { return "Preview " + m.bundle.Name }
+
+func (m Model) HandlesBack() bool { return false }
+
+func (m Model) ShortHelp() []string {
+	if m.loaded && m.err == nil && len(m.result.Changes) > 0 {
+		return []string{"↑↓ scroll", "pgup/pgdn page"}
+	}
+	return []string{}
+}
@@ -9,6 +9,8 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/reisset/mypctools/tui/internal/app"
 	"github.com/reisset/mypctools/tui/internal/bundle"
+	"github.com/reisset/mypctools/tui/internal/runner"
+	"github.com/reisset/mypctools/tui/internal/screen/transcript"
 	"github.com/reisset/mypctools/tui/internal/state"
 	"github.com/reisset/mypctools/tui/internal/theme"
 	"github.com/reisset/mypctools/tui/internal/ui"
@@ -25,23 +27,34 @@ type Model struct {
 	synced  []string
 	shimmer ui.Shimmer
 	fadeup  ui.FadeUp
+	pane    runner.Pane
 }
 
 func New(shared *state.Shared) Model {
 	return Model{
 		shared:  shared,
 		shimmer: ui.Shimmer{Text: "Pulling script changes..."},
+		pane:    runner.NewPane(shared),
 	}
 }
 
 func (m Model) Init() tea.Cmd {
-	cmd := exec.Command("git", "-C", m.shared.RootDir, "pull", "--ff-only")
-	return tea.ExecProcess(cmd, func(err error) tea.Msg {
-		return app.ExecDoneMsg{Err: err}
-	})
+	// Also called when returning from the log viewer; never rerun.
+	if m.done || m.syncing {
+		return nil
+	}
+	u := m.shared.Config.Update
+	cmd := exec.Command("git", "-C", m.shared.RootDir, "pull", "--ff-only", u.Remote, u.Branch)
+	return m.pane.Exec(cmd, "git pull")
 }
 
 func (m Model) Update(msg tea.Msg) (app.Screen, tea.Cmd) {
+	pane, cmd, handled := m.pane.Update(msg)
+	m.pane = pane
+	if handled {
+		return m, cmd
+	}
+
 	// Handle shimmer ticks during syncing.
 	if m.syncing {
 		if cmd := (&m.shimmer).Update(msg); cmd != nil {
@@ -83,6 +96,9 @@ func (m Model) Update(msg tea.Msg) (app.Screen, tea.Cmd) {
 
 	case tea.KeyMsg:
 		if m.done {
+			if msg.String() == "l" && m.err != nil && m.pane.TranscriptPath() != "" {
+				return m, app.Navigate(transcript.New(m.shared, m.pane.TranscriptPath()))
+			}
 			return m, app.PopScreen()
 		}
 	}
@@ -129,6 +145,9 @@ func (m Model) View() string {
 	if m.done && m.err != nil {
 		errLine := theme.ErrorStyle().Render("Failed to pull updates")
 		prompt := muted.Render("press any key to continue")
+		if m.pane.TranscriptPath() != "" {
+			prompt = muted.Render("press l to view the output log, any other key to continue")
+		}
 		return lipgloss.JoinVertical(lipgloss.Left, "", center(errLine), "", center(prompt))
 	}
 
@@ -147,14 +166,27 @@ func (m Model) View() string {
 		return center(m.shimmer.View())
 	}
 
-	return center(muted.Render("Pulling updates from origin/main..."))
+	if m.pane.Running() {
+		return m.pane.View("Pulling updates from " + m.shared.Config.Update.Upstream())
+	}
+
+	return center(muted.Render("Pulling updates from " + m.shared.Config.Update.Upstream() + "..."))
 }
 
 func (m Model) Title() string { return "Pull Updates" }
 
-func (m Model) HandlesBack() bool { return false }
+func (m Model) HandlesBack() bool { return m.pane.Running() }
+
+// HandlesInterrupt passes ctrl+c to the running command.
+func (m Model) HandlesInterrupt() bool { return m.pane.Running() }
 
 func (m Model) ShortHelp() []string {
+	if m.pane.Running() {
+		return m.pane.ShortHelp()
+	}
+	if m.done && m.err != nil && m.pane.TranscriptPath() != "" {
+		return []string{"l view log", "any key continue"}
+	}
 	if m.done {
 		return []string{"any key continue"}
 	}
@@ -1,11 +1,17 @@
 package scriptmenu
 
 import (
+	"fmt"
+	"strings"
+
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/reisset/mypctools/tui/internal/app"
+	"github.com/reisset/mypctools/tui/internal/backup"
 	"github.com/reisset/mypctools/tui/internal/bundle"
+	"github.com/reisset/mypctools/tui/internal/screen/backups"
 	"github.com/reisset/mypctools/tui/internal/screen/exec"
+	"github.com/reisset/mypctools/tui/internal/screen/preview"
 	"github.com/reisset/mypctools/tui/internal/state"
 	"github.com/reisset/mypctools/tui/internal/theme"
 	"github.com/reisset/mypctools/tui/internal/ui"
@@ -21,35 +27,50 @@ type menuItem struct {
 type Model struct {
 	shared     *state.Shared
 	bundle     bundle.Bundle
+	status     bundle.Status
 	installed  bool
 	items      []menuItem
 	cursor     int
 	confirming bool
+	confirmID  string          // "install" or "uninstall" while confirming
+	plan       []bundle.Bundle // Bundles to run, dependencies first
+	warnings   []string        // Shown under the confirmation question
+	installErr error           // Why the install can't start, until the next key
 }
 
 func New(shared *state.Shared, b bundle.Bundle) Model {
-	installed := bundle.IsInstalled(&b)
+	status := bundle.Detect(&b)
+	installed := status.State != bundle.StateMissing
+	saved, _ := backup.ForBundle(b.ID)
 	return Model{
 		shared:    shared,
 		bundle:    b,
+		status:    status,
 		installed: installed,
-		items:     buildItems(installed),
+		items:     buildItems(installed, b.DryRun, len(saved)),
 		cursor:    0,
 	}
 }
 
-func buildItems(installed bool) []menuItem {
+func buildItems(installed, canPreview bool, backupCount int) []menuItem {
+	var items []menuItem
 	if installed {
-		return []menuItem{
+		items = []menuItem{
 			{icon: "⟳", label: "Reinstall", id: "install"},
 			{icon: "✕", label: "Uninstall", id: "uninstall"},
-			{icon: "←", label: "Back", id: "back"},
 		}
+	} else {
+		items = []menuItem{
+			{icon: "+", label: "Install", id: "install"},
+		}
+	}
+	if canPreview {
+		items = append(items, menuItem{icon: "?", label: "Preview", id: "preview"})
 	}
-	return []menuItem{
-		{icon: "+", label: "Install", id: "install"},
-		{icon: "←", label: "Back", id: "back"},
+	if backupCount > 0 {
+		items = append(items, menuItem{icon: "↺", label: fmt.Sprintf("Backups (%d)", backupCount), id: "backups"})
 	}
+	return append(items, menuItem{icon: "←", label: "Back", id: "back"})
 }
 
 func (m Model) Init() tea.Cmd { return nil }
@@ -61,13 +82,14 @@ func (m Model) Update(msg tea.Msg) (app.Screen, tea.Cmd) {
 			switch msg.String() {
 			case "y", "Y":
 				m.confirming = false
-				return m, app.Navigate(exec.New(m.shared, m.bundle, "uninstall"))
+				return m, app.Navigate(exec.NewSequence(m.shared, m.plan, m.confirmID))
 			case "n", "N", "esc":
 				m.confirming = false
 			}
 			return m, nil
 		}
 
+		m.installErr = nil
 		switch msg.String() {
 		case "down":
 			m.cursor++
@@ -82,8 +104,11 @@ func (m Model) Update(msg tea.Msg) (app.Screen, tea.Cmd) {
 		case "enter", " ":
 			if m.cursor < len(m.items) {
 				id := m.items[m.cursor].id
-				if id == "uninstall" {
-					m.confirming = true
+				switch id {
+				case "install":
+					return m.prepareInstall()
+				case "uninstall":
+					m.prepareUninstall()
 					return m, nil
 				}
 				return m, m.handleSelection(id)
@@ -93,10 +118,62 @@ func (m Model) Update(msg tea.Msg) (app.Screen, tea.Cmd) {
 	return m, nil
 }
 
+// prepareInstall resolves missing dependencies and conflicts. When there are
+// none it starts the install directly; otherwise it asks for confirmation.
+func (m Model) prepareInstall() (app.Screen, tea.Cmd) {
+	missing, err := bundle.MissingRequirements(m.bundle)
+	if err != nil {
+		m.installErr = err
+		return m, nil
+	}
+	plan := append(missing, m.bundle)
+
+	var warnings []string
+	if len(missing) > 0 {
+		warnings = append(warnings, "Also installs required: "+bundle.Names(missing))
+	}
+	seen := make(map[string]bool)
+	var conflicts []bundle.Bundle
+	for _, b := range plan {
+		for _, c := range bundle.InstalledConflicts(b) {
+			if !seen[c.ID] {
+				seen[c.ID] = true
+				conflicts = append(conflicts, c)
+			}
+		}
+	}
+	if len(conflicts) > 0 {
+		warnings = append(warnings, "⚠ Conflicts with installed: "+bundle.Names(conflicts))
+	}
+
+	if len(warnings) == 0 {
+		return m, app.Navigate(exec.New(m.shared, m.bundle, "install"))
+	}
+	m.confirming = true
+	m.confirmID = "install"
+	m.plan = plan
+	m.warnings = warnings
+	return m, nil
+}
+
+// prepareUninstall asks for confirmation, warning about installed bundles
+// that depend on this one.
+func (m *Model) prepareUninstall() {
+	m.confirming = true
+	m.confirmID = "uninstall"
+	m.plan = []bundle.Bundle{m.bundle}
+	m.warnings = nil
+	if deps := bundle.Dependents(m.bundle.ID); len(deps) > 0 {
+		m.warnings = append(m.warnings, "⚠ Required by installed: "+bundle.Names(deps))
+	}
+}
+
 func (m Model) handleSelection(id string) tea.Cmd {
 	switch id {
-	case "install":
-		return app.Navigate(exec.New(m.shared, m.bundle, "install"))
+	case "preview":
+		return app.Navigate(preview.New(m.shared, m.bundle))
+	case "backups":
+		return app.Navigate(backups.New(m.shared, m.bundle.ID))
 	case "back":
 		return app.PopScreen()
 	}
@@ -111,12 +188,9 @@ func (m Model) View() string {
 
 	// Title + description block (centered)
 	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#ffffff"))
-	var titleLine string
-	if m.installed {
-		badge := ui.InstalledBadge()
-		titleLine = titleStyle.Render(m.bundle.Name) + "  " + badge
-	} else {
-		titleLine = titleStyle.Render(m.bundle.Name)
+	titleLine := titleStyle.Render(m.bundle.Name)
+	if badge := ui.BundleBadge(m.status.State); badge != "" {
+		titleLine += "  " + badge
 	}
 
 	titleBlock := lipgloss.NewStyle().Width(width).Align(lipgloss.Center).Render(titleLine)
@@ -127,15 +201,23 @@ func (m Model) View() string {
 		Render(m.bundle.Description)
 
 	if m.confirming {
-		confirmMsg := theme.WarningStyle().Render("Uninstall " + m.bundle.Name + "?")
+		center := lipgloss.NewStyle().Width(width).Align(lipgloss.Center)
+		verb := "Uninstall"
+		if m.confirmID == "install" {
+			verb = "Install"
+		}
+		confirmMsg := theme.WarningStyle().Render(verb + " " + m.bundle.Name + "?")
 		hint := theme.MutedStyle().Render("y confirm · n cancel")
-		return lipgloss.JoinVertical(lipgloss.Left,
-			titleBlock,
-			descBlock,
-			"",
-			lipgloss.NewStyle().Width(width).Align(lipgloss.Center).Render(confirmMsg),
-			lipgloss.NewStyle().Width(width).Align(lipgloss.Center).Render(hint),
-		)
+		parts := []string{titleBlock, descBlock, "", center.Render(confirmMsg)}
+		for _, w := range m.warnings {
+			style := theme.MutedStyle()
+			if strings.HasPrefix(w, "⚠") {
+				style = theme.WarningStyle()
+			}
+			parts = append(parts, center.Render(style.Render(w)))
+		}
+		parts = append(parts, center.Render(hint))
+		return lipgloss.JoinVertical(lipgloss.Left, parts...)
 	}
 
 	// Build list: insert separator before last item (Back).
@@ -159,12 +241,37 @@ func (m Model) View() string {
 
 	menuBlock := lipgloss.NewStyle().Width(width).Align(lipgloss.Center).Render(menu)
 
-	return lipgloss.JoinVertical(lipgloss.Left,
-		titleBlock,
-		descBlock,
-		"",
-		menuBlock,
-	)
+	parts := []string{titleBlock, descBlock}
+	if problems := m.renderProblems(width); problems != "" {
+		parts = append(parts, "", problems)
+	}
+	if m.installErr != nil {
+		line := theme.ErrorStyle().Render("✗ Can't install: " + m.installErr.Error())
+		parts = append(parts, "", lipgloss.NewStyle().Width(width).Align(lipgloss.Center).Render(line))
+	}
+	parts = append(parts, "", menuBlock)
+	return lipgloss.JoinVertical(lipgloss.Left, parts...)
+}
+
+// maxProblems caps how many partial/drifted reasons are listed.
+const maxProblems = 4
+
+// renderProblems lists why a bundle is partial or drifted.
+func (m Model) renderProblems(width int) string {
+	if len(m.status.Problems) == 0 {
+		return ""
+	}
+	lines := make([]string, 0, maxProblems+1)
+	for i, p := range m.status.Problems {
+		if i == maxProblems {
+			lines = append(lines, theme.MutedStyle().Render(
+				fmt.Sprintf("…and %d more", len(m.status.Problems)-maxProblems)))
+			break
+		}
+		lines = append(lines, theme.WarningStyle().Render("⚠ "+p))
+	}
+	return lipgloss.NewStyle().Width(width).Align(lipgloss.Center).
+		Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
 }
 
 func (m Model) Title() string {
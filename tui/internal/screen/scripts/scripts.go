@@ -1,38 +1,77 @@
 package scripts
 
 import (
+	"fmt"
+	"strings"
+
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/reisset/mypctools/tui/internal/app"
 	"github.com/reisset/mypctools/tui/internal/bundle"
+	"github.com/reisset/mypctools/tui/internal/screen/exec"
 	"github.com/reisset/mypctools/tui/internal/screen/scriptmenu"
 	"github.com/reisset/mypctools/tui/internal/state"
 	"github.com/reisset/mypctools/tui/internal/theme"
 	"github.com/reisset/mypctools/tui/internal/ui"
 )
 
+// statusesMsg carries freshly detected install states, one per bundle.
+type statusesMsg struct{ statuses []bundle.Status }
+
 // Model is the script bundles list screen.
+// Bundles can be opened one at a time or checked with space and installed as a batch.
 type Model struct {
-	shared  *state.Shared
-	bundles []bundle.Bundle
-	cursor  int
+	shared   *state.Shared
+	bundles  []bundle.Bundle
+	statuses []bundle.Status
+	broken   []*bundle.ManifestError // Left out, shown as warnings
+	cursor   int
+	selected map[string]bool
+
+	// Batch confirmation prompt
+	prompting   bool
+	promptIdx   int // 0 = stop on failure, 1 = continue on failure
+	batchPlan   []bundle.Bundle
+	batchNotice []string
+	planErr     error // Why the selection can't be installed, until the next key
 }
 
 func New(shared *state.Shared) Model {
 	return Model{
-		shared:  shared,
-		bundles: bundle.All(),
-		cursor:  0,
+		shared:   shared,
+		bundles:  bundle.All(),
+		broken:   bundle.Broken(),
+		cursor:   0,
+		selected: make(map[string]bool),
 	}
 }
 
+// Init detects install states in the background. It runs again on return
+// from an install/uninstall so the badges stay current.
 func (m Model) Init() tea.Cmd {
-	return nil
+	bundles := m.bundles
+	return func() tea.Msg {
+		return statusesMsg{statuses: detectAll(bundles)}
+	}
+}
+
+func detectAll(bundles []bundle.Bundle) []bundle.Status {
+	statuses := make([]bundle.Status, len(bundles))
+	for i := range bundles {
+		statuses[i] = bundle.Detect(&bundles[i])
+	}
+	return statuses
 }
 
 func (m Model) Update(msg tea.Msg) (app.Screen, tea.Cmd) {
 	switch msg := msg.(type) {
+	case statusesMsg:
+		m.statuses = msg.statuses
 	case tea.KeyMsg:
+		if m.prompting {
+			return m.updatePrompt(msg)
+		}
+		m.planErr = nil
 		switch msg.String() {
 		case "down":
 			m.cursor++
@@ -44,7 +83,27 @@ func (m Model) Update(msg tea.Msg) (app.Screen, tea.Cmd) {
 			if m.cursor < 0 {
 				m.cursor = len(m.bundles) - 1
 			}
-		case "enter", " ":
+		case " ":
+			if m.cursor < len(m.bundles) {
+				id := m.bundles[m.cursor].ID
+				if m.selected[id] {
+					delete(m.selected, id)
+				} else {
+					m.selected[id] = true
+				}
+			}
+		case "a":
+			if len(m.selected) == len(m.bundles) {
+				m.selected = make(map[string]bool)
+			} else {
+				for _, b := range m.bundles {
+					m.selected[b.ID] = true
+				}
+			}
+		case "enter":
+			if len(m.selected) > 0 {
+				return m.startPrompt()
+			}
 			if m.cursor < len(m.bundles) {
 				return m, app.Navigate(scriptmenu.New(m.shared, m.bundles[m.cursor]))
 			}
@@ -53,6 +112,78 @@ func (m Model) Update(msg tea.Msg) (app.Screen, tea.Cmd) {
 	return m, nil
 }
 
+// startPrompt resolves the selected bundles (plus missing dependencies) into
+// install order and asks whether to stop or continue on failure.
+func (m Model) startPrompt() (app.Screen, tea.Cmd) {
+	var ids []string
+	for _, b := range m.bundles {
+		if m.selected[b.ID] {
+			ids = append(ids, b.ID)
+		}
+	}
+	order, err := bundle.InstallOrder(ids)
+	if err != nil {
+		m.planErr = err
+		return m, nil
+	}
+
+	// Keep explicitly selected bundles (reinstall) and missing dependencies only.
+	var plan, deps []bundle.Bundle
+	for _, b := range order {
+		if m.selected[b.ID] {
+			plan = append(plan, b)
+		} else if !bundle.IsInstalled(&b) {
+			plan = append(plan, b)
+			deps = append(deps, b)
+		}
+	}
+
+	var notice []string
+	if len(deps) > 0 {
+		notice = append(notice, "Also installs required: "+bundle.Names(deps))
+	}
+	for i, a := range plan {
+		for _, b := range plan[i+1:] {
+			if bundle.ConflictsWith(a, b) {
+				notice = append(notice, fmt.Sprintf("⚠ %s conflicts with %s", a.Name, b.Name))
+			}
+		}
+		if c := bundle.InstalledConflicts(a); len(c) > 0 {
+			notice = append(notice, fmt.Sprintf("⚠ %s conflicts with installed: %s", a.Name, bundle.Names(c)))
+		}
+	}
+
+	m.prompting = true
+	m.promptIdx = 0
+	m.batchPlan = plan
+	m.batchNotice = notice
+	return m, nil
+}
+
+func (m Model) updatePrompt(msg tea.KeyMsg) (app.Screen, tea.Cmd) {
+	switch msg.String() {
+	case "up", "down", "tab":
+		m.promptIdx = 1 - m.promptIdx
+	case "s":
+		return m.runBatch(false)
+	case "c":
+		return m.runBatch(true)
+	case "enter", " ":
+		return m.runBatch(m.promptIdx == 1)
+	case "n", "esc":
+		m.prompting = false
+	}
+	return m, nil
+}
+
+func (m Model) runBatch(continueOnError bool) (app.Screen, tea.Cmd) {
+	plan := m.batchPlan
+	m.prompting = false
+	m.batchPlan = nil
+	m.selected = make(map[string]bool)
+	return m, app.Navigate(exec.NewBatch(m.shared, plan, "install", continueOnError))
+}
+
 func (m Model) View() string {
 	width := m.shared.TerminalWidth
 	if width == 0 {
@@ -65,23 +196,50 @@ func (m Model) View() string {
 		Align(lipgloss.Center).
 		Render("Personal script bundles and configs")
 
+	if m.prompting {
+		return lipgloss.JoinVertical(lipgloss.Left, subtitle, "", m.viewPrompt(width))
+	}
+
+	if n := len(m.selected); n > 0 {
+		subtitle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.Current.Primary)).
+			Width(width).
+			Align(lipgloss.Center).
+			Render(fmt.Sprintf("%d selected · enter to install", n))
+	}
+
 	items := make([]ui.ListItem, len(m.bundles))
 	for i, b := range m.bundles {
 		var suffix string
-		if bundle.IsInstalled(&b) {
-			suffix = ui.InstalledBadge()
-		} else if b.PlatformSuffix != "" {
+		if i < len(m.statuses) {
+			suffix = ui.BundleBadge(m.statuses[i].State)
+		}
+		if suffix == "" && b.PlatformSuffix != "" {
 			suffix = theme.MutedStyle().Render(b.PlatformSuffix)
 		}
+		icon := "◇"
+		if m.selected[b.ID] {
+			icon = "◆"
+		}
 		items[i] = ui.ListItem{
-			Icon:        "◇",
+			Icon:        icon,
 			Label:       b.Name,
 			Suffix:      suffix,
 			Description: b.Description,
 		}
 	}
 
-	listHeight := m.shared.ContentHeight - 4
+	var warnings []string
+	if m.planErr != nil {
+		line := theme.ErrorStyle().Render("✗ Can't install: " + m.planErr.Error())
+		warnings = append(warnings, lipgloss.NewStyle().Width(width).Align(lipgloss.Center).Render(line))
+	}
+	for _, e := range m.broken {
+		line := theme.WarningStyle().Render(fmt.Sprintf("⚠ Skipped %s: %v", e.ID, e.Err))
+		warnings = append(warnings, lipgloss.NewStyle().Width(width).Align(lipgloss.Center).Render(line))
+	}
+
+	listHeight := m.shared.ContentHeight - 4 - len(warnings)
 	if listHeight < 5 {
 		listHeight = 5
 	}
@@ -97,19 +255,48 @@ func (m Model) View() string {
 		Align(lipgloss.Center).
 		Render(menu)
 
-	return lipgloss.JoinVertical(lipgloss.Left,
-		subtitle,
-		"",
-		menuBlock,
-	)
+	parts := append([]string{subtitle}, warnings...)
+	return lipgloss.JoinVertical(lipgloss.Left, append(parts, "", menuBlock)...)
+}
+
+func (m Model) viewPrompt(width int) string {
+	center := func(s string) string {
+		return lipgloss.NewStyle().Width(width).Align(lipgloss.Center).Render(s)
+	}
+	question := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#ffffff")).
+		Render(fmt.Sprintf("Install %d bundles?", len(m.batchPlan)))
+	order := theme.MutedStyle().Render(bundle.Names(m.batchPlan))
+
+	parts := []string{center(question), center(order)}
+	for _, n := range m.batchNotice {
+		style := theme.MutedStyle()
+		if strings.HasPrefix(n, "⚠") {
+			style = theme.WarningStyle()
+		}
+		parts = append(parts, center(style.Render(n)))
+	}
+
+	items := []ui.ListItem{
+		{Icon: "■", Label: "Stop on first failure"},
+		{Icon: "▶", Label: "Continue on failure"},
+	}
+	menu := ui.RenderList(items, m.promptIdx, ui.ListConfig{Width: 40, MaxInnerWidth: 40})
+	parts = append(parts, "", center(menu))
+	return lipgloss.JoinVertical(lipgloss.Left, parts...)
 }
 
 func (m Model) Title() string {
 	return "My Scripts"
 }
 
-func (m Model) HandlesBack() bool { return false }
+func (m Model) HandlesBack() bool { return m.prompting }
 
 func (m Model) ShortHelp() []string {
-	return []string{"↑↓ navigate", "enter select"}
+	if m.prompting {
+		return []string{"enter run", "s stop", "c continue", "n cancel"}
+	}
+	if len(m.selected) > 0 {
+		return []string{"space toggle", "a all", "enter install"}
+	}
+	return []string{"↑↓ navigate", "space select", "a all", "enter open"}
 }
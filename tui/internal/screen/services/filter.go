@@ -0,0 +1,105 @@
+package services
+
+import (
+	"strings"
+
+	"github.com/reisset/mypctools/tui/internal/system"
+)
+
+// quickFilter narrows the service list by state, cycled with "f".
+type quickFilter int
+
+const (
+	filterAll quickFilter = iota
+	filterRunning
+	filterFailed
+	filterEnabled
+	filterDisabled
+	filterCount
+)
+
+func (f quickFilter) String() string {
+	return [...]string{"all", "running", "failed", "enabled", "disabled"}[f]
+}
+
+func (f quickFilter) match(s system.ServiceStatus) bool {
+	switch f {
+	case filterRunning:
+		return s.Active == "active"
+	case filterFailed:
+		return s.Active == "failed"
+	case filterEnabled:
+		return strings.HasPrefix(s.Enabled, "enabled") // Includes enabled-runtime
+	case filterDisabled:
+		return s.Enabled == "disabled"
+	}
+	return true
+}
+
+// sortOrder orders the service list, cycled with "s".
+type sortOrder int
+
+const (
+	sortName sortOrder = iota
+	sortState
+	sortMemory
+	sortCount
+)
+
+func (o sortOrder) String() string {
+	return [...]string{"name", "state", "memory"}[o]
+}
+
+// compare orders a before b: by name, by state (failed first, stopped
+// last), or by memory (largest first, untracked last). Ties go by name.
+func (o sortOrder) compare(a, b system.ServiceStatus) int {
+	switch o {
+	case sortState:
+		if d := stateRank(a.Active) - stateRank(b.Active); d != 0 {
+			return d
+		}
+	case sortMemory:
+		if a.Memory != b.Memory {
+			if a.Memory > b.Memory {
+				return -1
+			}
+			return 1
+		}
+	}
+	return strings.Compare(a.Name, b.Name)
+}
+
+func stateRank(active string) int {
+	switch active {
+	case "failed":
+		return 0
+	case "active":
+		return 1
+	case "inactive":
+		return 3
+	}
+	return 2 // activating, deactivating, reloading, unknown
+}
+
+// matchSearch reports whether query fuzzily matches the service's name (its
+// letters appear in order) or appears in its description. Case-insensitive.
+func matchSearch(query string, s system.ServiceStatus) bool {
+	if query == "" {
+		return true
+	}
+	query = strings.ToLower(query)
+	return fuzzyMatch(query, strings.ToLower(s.Name)) ||
+		strings.Contains(strings.ToLower(s.Description), query)
+}
+
+// fuzzyMatch reports whether the runes of query appear in s in order.
+func fuzzyMatch(query, s string) bool {
+	for _, r := range query {
+		i := strings.IndexRune(s, r)
+		if i < 0 {
+			return false
+		}
+		s = s[i+len(string(r)):]
+	}
+	return true
+}
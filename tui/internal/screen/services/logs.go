@@ -0,0 +1,374 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+	"github.com/reisset/mypctools/tui/internal/app"
+	"github.com/reisset/mypctools/tui/internal/journal"
+	"github.com/reisset/mypctools/tui/internal/state"
+	"github.com/reisset/mypctools/tui/internal/system"
+	"github.com/reisset/mypctools/tui/internal/theme"
+)
+
+// maxEntries caps how many journal entries the viewer keeps.
+const maxEntries = 5000
+
+type streamStartedMsg struct {
+	gen    int64 // streamSlot generation the stream was started for
+	stream *journal.Stream
+	err    error
+}
+
+// streamSlot owns the viewer's journalctl stream. It is shared by every copy
+// of the model and by pending start commands, so a stream that starts after
+// it was superseded, or after the viewer was closed, is stopped instead of
+// left running.
+type streamSlot struct {
+	mu     sync.Mutex
+	gen    int64           // Bumped by every begin and close
+	stream *journal.Stream // The accepted stream, if any
+}
+
+// begin stops the current stream and returns the generation a new one must
+// present to be accepted.
+func (s *streamSlot) begin() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stopLocked()
+	return s.gen
+}
+
+// accept keeps st if it belongs to the current generation, else closes it.
+func (s *streamSlot) accept(gen int64, st *journal.Stream) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if gen != s.gen {
+		st.Close()
+		return false
+	}
+	s.stream = st
+	return true
+}
+
+// current reports whether gen is still the generation being shown.
+func (s *streamSlot) current(gen int64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return gen == s.gen
+}
+
+// close stops the current stream and rejects any still starting.
+func (s *streamSlot) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stopLocked()
+}
+
+func (s *streamSlot) stopLocked() {
+	if s.stream != nil {
+		s.stream.Close()
+		s.stream = nil
+	}
+	s.gen++
+}
+
+type entriesMsg struct {
+	id      int64
+	entries []journal.Entry
+}
+
+type streamEndedMsg struct {
+	id  int64
+	err error
+}
+
+// LogsModel streams a service's journal into a scrollable viewport.
+type LogsModel struct {
+	shared    *state.Shared
+	slot      *streamSlot
+	scope     system.Scope
+	unit      string
+	rangeIdx  int             // Into journal.Ranges
+	stream    *journal.Stream // Received from slot; entries from others are stale
+	live      bool            // Stream is still open
+	entries   []journal.Entry
+	shown     int // Entries passing the filter
+	err       error
+	follow    bool // Stick to the bottom as entries arrive
+	filter    string
+	filtering bool // Typing the filter
+	viewport  viewport.Model
+}
+
+// NewLogs creates a log viewer for one service.
+func NewLogs(shared *state.Shared, scope system.Scope, unit string) LogsModel {
+	m := LogsModel{shared: shared, slot: &streamSlot{}, scope: scope, unit: unit, follow: true}
+	m.viewport = viewport.New(m.size())
+	return m
+}
+
+func (m LogsModel) Init() tea.Cmd {
+	if m.stream != nil {
+		return nil
+	}
+	return m.start()
+}
+
+// start opens a stream for the current range, replacing any open one.
+func (m LogsModel) start() tea.Cmd {
+	unit, user, r := m.unit, m.scope == system.ScopeUser, journal.Ranges[m.rangeIdx]
+	slot := m.slot
+	gen := slot.begin()
+	return func() tea.Msg {
+		s, err := journal.Follow(unit, user, r)
+		if err != nil {
+			return streamStartedMsg{gen: gen, err: err}
+		}
+		if !slot.accept(gen, s) {
+			return nil // Superseded or closed while starting
+		}
+		return streamStartedMsg{gen: gen, stream: s}
+	}
+}
+
+// wait reads the next batch of entries from the current stream.
+func (m LogsModel) wait() tea.Cmd {
+	s := m.stream
+	return func() tea.Msg {
+		entries, ok := s.Next(500)
+		if !ok {
+			return streamEndedMsg{id: s.ID, err: s.Err()}
+		}
+		return entriesMsg{id: s.ID, entries: entries}
+	}
+}
+
+func (m LogsModel) Update(msg tea.Msg) (app.Screen, tea.Cmd) {
+	switch msg := msg.(type) {
+	case streamStartedMsg:
+		if !m.slot.current(msg.gen) {
+			return m, nil // Replaced by a later start, which closed it
+		}
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+		m.stream = msg.stream
+		m.live = true
+		return m, m.wait()
+
+	case entriesMsg:
+		if m.stream == nil || msg.id != m.stream.ID {
+			return m, nil
+		}
+		m.entries = append(m.entries, msg.entries...)
+		if over := len(m.entries) - maxEntries; over > 0 {
+			m.entries = append([]journal.Entry(nil), m.entries[over:]...)
+		}
+		m.refresh()
+		return m, m.wait()
+
+	case streamEndedMsg:
+		if m.stream == nil || msg.id != m.stream.ID {
+			return m, nil
+		}
+		m.live = false
+		m.err = msg.err
+		return m, nil
+
+	case tea.WindowSizeMsg:
+		m.viewport.Width, m.viewport.Height = m.size()
+		m.refresh()
+
+	case tea.KeyMsg:
+		if m.filtering {
+			return m.updateFilter(msg)
+		}
+		switch msg.String() {
+		case "esc":
+			m.close()
+			return m, app.PopScreen()
+		case "/":
+			m.filtering = true
+		case "r":
+			m.close()
+			m.rangeIdx = (m.rangeIdx + 1) % len(journal.Ranges)
+			m.entries = nil
+			m.err = nil
+			m.follow = true
+			m.refresh()
+			return m, m.start()
+		case "f":
+			m.follow = !m.follow
+			if m.follow {
+				m.viewport.GotoBottom()
+			}
+		case "up", "k":
+			m.viewport.LineUp(1)
+			m.follow = false
+		case "down", "j":
+			m.viewport.LineDown(1)
+			m.follow = m.viewport.AtBottom()
+		case "pgup":
+			m.viewport.HalfViewUp()
+			m.follow = false
+		case "pgdown", " ":
+			m.viewport.HalfViewDown()
+			m.follow = m.viewport.AtBottom()
+		case "home":
+			m.viewport.GotoTop()
+			m.follow = false
+		case "end":
+			m.viewport.GotoBottom()
+			m.follow = true
+		}
+	}
+	return m, nil
+}
+
+func (m LogsModel) updateFilter(msg tea.KeyMsg) (app.Screen, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		m.filtering = false
+	case tea.KeyEsc:
+		m.filtering = false
+		m.filter = ""
+	case tea.KeyBackspace:
+		if r := []rune(m.filter); len(r) > 0 {
+			m.filter = string(r[:len(r)-1])
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		m.filter += string(msg.Runes)
+	default:
+		return m, nil
+	}
+	m.refresh()
+	return m, nil
+}
+
+// close stops the current stream, and any still starting.
+func (m *LogsModel) close() {
+	m.slot.close()
+	m.stream = nil
+	m.live = false
+}
+
+// matches reports whether e passes the search filter (case-insensitive).
+func (m LogsModel) matches(e journal.Entry) bool {
+	if m.filter == "" {
+		return true
+	}
+	needle := strings.ToLower(m.filter)
+	return strings.Contains(strings.ToLower(e.Message), needle) ||
+		strings.Contains(strings.ToLower(e.Ident), needle)
+}
+
+// refresh renders the matching entries, coloured by priority.
+func (m *LogsModel) refresh() {
+	var out []string
+	for _, e := range m.entries {
+		if !m.matches(e) {
+			continue
+		}
+		line := runewidth.Truncate(strings.ReplaceAll(e.String(), "\t", "    "), m.viewport.Width, "…")
+		switch {
+		case e.Priority <= journal.PriorityErr:
+			line = theme.ErrorStyle().Render(line)
+		case e.Priority == journal.PriorityWarning:
+			line = theme.WarningStyle().Render(line)
+		case e.Priority == journal.PriorityNotice:
+			line = lipgloss.NewStyle().Bold(true).Render(line)
+		case e.Priority >= journal.PriorityDebug:
+			line = theme.MutedStyle().Render(line)
+		}
+		out = append(out, line)
+	}
+	m.shown = len(out)
+	m.viewport.SetContent(strings.Join(out, "\n"))
+	if m.follow {
+		m.viewport.GotoBottom()
+	}
+}
+
+func (m LogsModel) size() (int, int) {
+	w := m.shared.TerminalWidth - 4
+	if w < 20 {
+		w = 76
+	}
+	h := m.shared.ContentHeight - 2
+	if h < 5 {
+		h = 5
+	}
+	return w, h
+}
+
+func (m LogsModel) View() string {
+	width := m.shared.TerminalWidth
+	if width == 0 {
+		width = 80
+	}
+	center := func(s string) string {
+		return lipgloss.NewStyle().Width(width).Align(lipgloss.Center).Render(s)
+	}
+	muted := theme.MutedStyle()
+
+	status := []string{journal.Ranges[m.rangeIdx].Label}
+	switch {
+	case m.live && m.follow:
+		status = append(status, "following")
+	case m.live:
+		status = append(status, "paused")
+	}
+	status = append(status, fmt.Sprintf("%d entries", len(m.entries)))
+	if m.filtering {
+		status = append(status, "filter: "+m.filter+"▏")
+	} else if m.filter != "" {
+		status = append(status, "filter: "+m.filter)
+	}
+	statusLine := muted.Render(strings.Join(status, "  ·  "))
+	if m.err != nil {
+		statusLine = theme.WarningStyle().Render("⚠  " + m.err.Error())
+	}
+
+	body := lipgloss.NewStyle().PaddingLeft(2).Render(m.viewport.View())
+	if text := m.emptyText(); text != "" {
+		body = center(muted.Render(text))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, center(statusLine), body)
+}
+
+// emptyText explains an empty viewport, or is "" when there is output.
+func (m LogsModel) emptyText() string {
+	switch {
+	case len(m.entries) > 0:
+		if m.shown == 0 {
+			return "No entries match the filter"
+		}
+		return ""
+	case m.err != nil:
+		return "No journal entries"
+	case m.stream == nil:
+		return "Loading journal..."
+	case m.live:
+		return "Waiting for entries..."
+	}
+	return "No journal entries in " + journal.Ranges[m.rangeIdx].Label
+}
+
+func (m LogsModel) Title() string { return "Logs: " + m.unit }
+
+// HandlesBack is always true so esc can stop journalctl before leaving.
+func (m LogsModel) HandlesBack() bool { return true }
+
+func (m LogsModel) ShortHelp() []string {
+	if m.filtering {
+		return []string{"enter apply", "esc clear"}
+	}
+	return []string{"↑↓ scroll", "/ filter", "r range", "f follow", "esc back"}
+}
@@ -0,0 +1,42 @@
+package services
+
+import (
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/reisset/mypctools/tui/internal/journal"
+	"github.com/reisset/mypctools/tui/internal/state"
+	"github.com/reisset/mypctools/tui/internal/system"
+)
+
+func TestLogsDropsStaleStreams(t *testing.T) {
+	t.Setenv(journal.FileEnv, filepath.Join("..", "..", "journal", "testdata", "sshd.json"))
+	shared := &state.Shared{TerminalWidth: 80, ContentHeight: 20}
+
+	// Closed before its stream started: the stream is stopped, not delivered.
+	m := NewLogs(shared, system.ScopeSystem, "sshd")
+	first := m.Init()
+	m.close()
+	if msg := first(); msg != nil {
+		t.Fatalf("stream started after close was delivered: %T", msg)
+	}
+
+	// Range changed twice before the first stream arrived: only the last counts.
+	m = NewLogs(shared, system.ScopeSystem, "sshd")
+	first = m.Init()
+	screen, second := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	if msg := first(); msg != nil {
+		t.Fatalf("superseded stream was delivered: %T", msg)
+	}
+	msg, ok := second().(streamStartedMsg)
+	if !ok || msg.stream == nil {
+		t.Fatalf("current stream not delivered: %#v", msg)
+	}
+	screen, _ = screen.Update(msg)
+	logs := screen.(LogsModel)
+	if logs.stream != msg.stream {
+		t.Fatal("viewer did not keep the current stream")
+	}
+	logs.close()
+}
@@ -2,7 +2,10 @@ package services
 
 import (
 	"fmt"
+	"os/exec"
+	"slices"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -22,9 +25,32 @@ const (
 	actionRestart
 	actionEnable
 	actionDisable
+	actionLogs
 	actionBack
 )
 
+// verb is the Manager action for a, or "" for screen actions.
+func (a detailAction) verb() string {
+	switch a {
+	case actionStart:
+		return "start"
+	case actionStop:
+		return "stop"
+	case actionRestart:
+		return "restart"
+	case actionEnable:
+		return "enable"
+	case actionDisable:
+		return "disable"
+	}
+	return ""
+}
+
+// runInTerminalMsg hands the terminal to an action that needs it (sudo).
+type runInTerminalMsg struct {
+	cmd *exec.Cmd
+}
+
 type actionItem struct {
 	icon   string
 	label  string
@@ -34,19 +60,25 @@ type actionItem struct {
 // ServiceDetailModel shows stats and actions for a single service.
 type ServiceDetailModel struct {
 	shared      *state.Shared
+	mgr         system.Manager
+	scope       system.Scope
 	serviceName string
 	status      system.ServiceStatus
 	cursor      int
 	items       []actionItem
+	running     bool // An action is in progress
 	actionDone  bool
 	actionErr   error
 	lastAction  detailAction
+	watch       *system.Watch // Live updates, while the screen is on top
 }
 
-func NewServiceDetail(shared *state.Shared, serviceName string) ServiceDetailModel {
-	status := system.GetServiceStatus(serviceName)
+func NewServiceDetail(shared *state.Shared, mgr system.Manager, scope system.Scope, serviceName string) ServiceDetailModel {
+	status := mgr.Status(serviceName)
 	return ServiceDetailModel{
 		shared:      shared,
+		mgr:         mgr,
+		scope:       scope,
 		serviceName: serviceName,
 		status:      status,
 		items:       buildMenuItems(status),
@@ -67,26 +99,82 @@ func buildMenuItems(status system.ServiceStatus) []actionItem {
 	} else {
 		items = append(items, actionItem{icon: "●", label: "Enable", action: actionEnable})
 	}
+	items = append(items, actionItem{icon: "☰", label: "Logs", action: actionLogs})
 	items = append(items, actionItem{icon: "←", label: "Back", action: actionBack})
 	return items
 }
 
-func (m ServiceDetailModel) Init() tea.Cmd { return nil }
+func (m ServiceDetailModel) Init() tea.Cmd { return startWatch(m.mgr) }
+
+// setStatus shows status and rebuilds the menu to match.
+func (m *ServiceDetailModel) setStatus(status system.ServiceStatus) {
+	m.status = status
+	m.items = buildMenuItems(m.status)
+	if m.cursor >= len(m.items) {
+		m.cursor = len(m.items) - 1
+	}
+}
+
+// statusReadMsg carries a status re-read after an action or a change the
+// watch reported.
+type statusReadMsg struct {
+	status system.ServiceStatus
+}
+
+// readStatus re-reads the service off the UI thread.
+func (m ServiceDetailModel) readStatus() tea.Cmd {
+	mgr, name := m.mgr, m.serviceName
+	return func() tea.Msg { return statusReadMsg{status: mgr.Status(name)} }
+}
+
+// closeWatch stops live updates before the screen leaves or is covered.
+func (m *ServiceDetailModel) closeWatch() {
+	if m.watch != nil {
+		m.watch.Close()
+		m.watch = nil
+	}
+}
 
 func (m ServiceDetailModel) Update(msg tea.Msg) (app.Screen, tea.Cmd) {
 	switch msg := msg.(type) {
+	case runInTerminalMsg:
+		return m, tea.ExecProcess(msg.cmd, func(err error) tea.Msg { return app.ExecDoneMsg{Err: err} })
+
 	case app.ExecDoneMsg:
+		m.running = false
 		m.actionDone = true
 		m.actionErr = msg.Err
 		m.logServiceAction()
-		m.status = system.GetServiceStatus(m.serviceName)
-		m.items = buildMenuItems(m.status)
-		if m.cursor >= len(m.items) {
-			m.cursor = len(m.items) - 1
+		return m, m.readStatus()
+
+	case watchStartedMsg:
+		m.closeWatch()
+		m.watch = msg.watch
+		return m, waitChanges(m.watch)
+
+	case unitsChangedMsg:
+		if m.watch == nil || msg.id != m.watch.ID {
+			return m, nil
+		}
+		if slices.Contains(msg.names, m.serviceName) {
+			return m, tea.Batch(m.readStatus(), waitChanges(m.watch))
+		}
+		return m, waitChanges(m.watch)
+
+	case statusReadMsg:
+		if msg.status.Name == m.serviceName {
+			m.setStatus(msg.status)
 		}
 		return m, nil
 
 	case tea.KeyMsg:
+		if m.running {
+			return m, nil
+		}
+		if msg.String() == "esc" {
+			m.closeWatch()
+			return m, app.PopScreen()
+		}
 		if m.actionDone {
 			m.actionDone = false
 			m.actionErr = nil
@@ -104,56 +192,46 @@ func (m ServiceDetailModel) Update(msg tea.Msg) (app.Screen, tea.Cmd) {
 				m.cursor = len(m.items) - 1
 			}
 		case "enter", " ":
-			m.lastAction = m.items[m.cursor].action
-			return m, m.handleAction(m.items[m.cursor].action)
+			return m.handleAction(m.items[m.cursor].action)
 		}
 	}
 	return m, nil
 }
 
-func (m ServiceDetailModel) handleAction(action detailAction) tea.Cmd {
+func (m ServiceDetailModel) handleAction(action detailAction) (app.Screen, tea.Cmd) {
 	switch action {
 	case actionBack:
-		return app.PopScreen()
-	case actionStart:
-		cmd := system.ServiceActionCmd(m.serviceName, "start")
-		return tea.ExecProcess(cmd, func(err error) tea.Msg { return app.ExecDoneMsg{Err: err} })
-	case actionStop:
-		cmd := system.ServiceActionCmd(m.serviceName, "stop")
-		return tea.ExecProcess(cmd, func(err error) tea.Msg { return app.ExecDoneMsg{Err: err} })
-	case actionRestart:
-		cmd := system.ServiceActionCmd(m.serviceName, "restart")
-		return tea.ExecProcess(cmd, func(err error) tea.Msg { return app.ExecDoneMsg{Err: err} })
-	case actionEnable:
-		cmd := system.ServiceActionCmd(m.serviceName, "enable")
-		return tea.ExecProcess(cmd, func(err error) tea.Msg { return app.ExecDoneMsg{Err: err} })
-	case actionDisable:
-		cmd := system.ServiceActionCmd(m.serviceName, "disable")
-		return tea.ExecProcess(cmd, func(err error) tea.Msg { return app.ExecDoneMsg{Err: err} })
+		m.closeWatch()
+		return m, app.PopScreen()
+	case actionLogs:
+		m.closeWatch() // Init starts a new one on return
+		return m, app.Navigate(NewLogs(m.shared, m.scope, m.serviceName))
+	}
+	m.lastAction = action
+	m.running = true
+	mgr, name, verb := m.mgr, m.serviceName, action.verb()
+	return m, func() tea.Msg {
+		cmd, err := mgr.Action(name, verb)
+		if cmd != nil {
+			return runInTerminalMsg{cmd: cmd}
+		}
+		return app.ExecDoneMsg{Err: err}
 	}
-	return nil
 }
 
 func (m ServiceDetailModel) logServiceAction() {
-	var actionName string
-	switch m.lastAction {
-	case actionStart:
-		actionName = "start"
-	case actionStop:
-		actionName = "stop"
-	case actionRestart:
-		actionName = "restart"
-	case actionEnable:
-		actionName = "enable"
-	case actionDisable:
-		actionName = "disable"
-	default:
+	actionName := m.lastAction.verb()
+	if actionName == "" {
 		return
 	}
+	name := m.serviceName
+	if m.scope == system.ScopeUser {
+		name += " (user)"
+	}
 	if m.actionErr != nil {
-		logging.LogAction(fmt.Sprintf("Service %s %s failed", m.serviceName, actionName))
+		logging.LogAction(fmt.Sprintf("Service %s %s failed", name, actionName))
 	} else {
-		logging.LogAction(fmt.Sprintf("Service %s %s", m.serviceName, actionName))
+		logging.LogAction(fmt.Sprintf("Service %s %s", name, actionName))
 	}
 }
 
@@ -167,11 +245,15 @@ func (m ServiceDetailModel) View() string {
 	sep := "  " + theme.HelpDividerStyle().Render(strings.Repeat("─", 40))
 
 	// Status row
+	state := m.status.Active
+	if m.status.SubState != "" && m.status.SubState != m.status.Active {
+		state += " (" + m.status.SubState + ")"
+	}
 	var activeStr string
 	if m.status.Active == "active" {
-		activeStr = theme.SuccessStyle().Render("● active")
+		activeStr = theme.SuccessStyle().Render("● " + state)
 	} else {
-		activeStr = theme.ErrorStyle().Render("○ " + m.status.Active)
+		activeStr = theme.ErrorStyle().Render("○ " + state)
 	}
 
 	var enabledStr string
@@ -187,22 +269,73 @@ func (m ServiceDetailModel) View() string {
 			value,
 		)
 	}
+	orDash := func(s string) string {
+		if s == "" {
+			return muted.Render("—")
+		}
+		return muted.Render(s)
+	}
 
-	pidStr := muted.Render("—")
-	if m.status.PID != "" {
-		pidStr = muted.Render(m.status.PID)
+	var uptime, memory, cpu, tasks string
+	if m.status.Active == "active" && !m.status.Since.IsZero() {
+		uptime = formatUptime(time.Since(m.status.Since))
+	}
+	if m.status.Memory >= 0 {
+		memory = system.FormatSize(m.status.Memory)
+	}
+	if m.status.CPU >= 0 {
+		cpu = m.status.CPU.Round(10 * time.Millisecond).String()
+	}
+	if m.status.Tasks >= 0 {
+		tasks = fmt.Sprint(m.status.Tasks)
+	}
+	restartStr := muted.Render(fmt.Sprint(m.status.Restarts))
+	if m.status.Restarts > 0 {
+		restartStr = theme.WarningStyle().Render(fmt.Sprint(m.status.Restarts))
 	}
 
 	statusRow := lipgloss.JoinHorizontal(lipgloss.Top,
-		lipgloss.NewStyle().Width(20).Render(col("STATUS", activeStr)),
-		lipgloss.NewStyle().Width(20).Render(col("ENABLED", enabledStr)),
-		lipgloss.NewStyle().Width(16).Render(col("PID", pidStr)),
+		lipgloss.NewStyle().Width(24).Render(col("STATUS", activeStr)),
+		lipgloss.NewStyle().Width(12).Render(col("ENABLED", enabledStr)),
+		lipgloss.NewStyle().Width(10).Render(col("PID", orDash(m.status.PID))),
+		lipgloss.NewStyle().Width(12).Render(col("UPTIME", orDash(uptime))),
 	)
+	usageRow := lipgloss.JoinHorizontal(lipgloss.Top,
+		lipgloss.NewStyle().Width(24).Render(col("MEMORY", orDash(memory))),
+		lipgloss.NewStyle().Width(12).Render(col("CPU", orDash(cpu))),
+		lipgloss.NewStyle().Width(10).Render(col("TASKS", orDash(tasks))),
+		lipgloss.NewStyle().Width(12).Render(col("RESTARTS", restartStr)),
+	)
+
+	// Last result and unit file on one line
+	var footer []string
+	switch r := m.status.Result; {
+	case r == "success":
+		footer = append(footer, muted.Render("last run: success"))
+	case r != "":
+		footer = append(footer, theme.WarningStyle().Render("last run: "+r))
+	}
+	if m.status.Path != "" {
+		footer = append(footer, muted.Render(m.status.Path))
+	}
 
-	statsBlock := lipgloss.NewStyle().Width(width).PaddingLeft(2).Render(statusRow)
+	var statsLines []string
+	if m.status.Description != "" {
+		statsLines = append(statsLines, m.status.Description)
+	}
+	statsLines = append(statsLines, statusRow, usageRow)
+	if len(footer) > 0 {
+		statsLines = append(statsLines, strings.Join(footer, muted.Render("  ·  ")))
+	}
+	statsBlock := lipgloss.NewStyle().Width(width).PaddingLeft(2).Render(
+		lipgloss.JoinVertical(lipgloss.Left, statsLines...))
 
 	// Action result
 	var resultBlock string
+	if m.running {
+		line := muted.Render(fmt.Sprintf("Running %s…", m.lastAction.verb()))
+		resultBlock = lipgloss.NewStyle().Width(width).Align(lipgloss.Center).Render(line)
+	}
 	if m.actionDone {
 		var line string
 		if m.actionErr != nil {
@@ -242,10 +375,36 @@ func (m ServiceDetailModel) View() string {
 	return lipgloss.JoinVertical(lipgloss.Left, parts...)
 }
 
-func (m ServiceDetailModel) Title() string     { return m.serviceName }
-func (m ServiceDetailModel) HandlesBack() bool { return false }
+// formatUptime renders a duration to its two largest units ("3d 4h").
+func formatUptime(d time.Duration) string {
+	days := int(d.Hours()) / 24
+	hours := int(d.Hours()) % 24
+	mins := int(d.Minutes()) % 60
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd %dh", days, hours)
+	case hours > 0:
+		return fmt.Sprintf("%dh %dm", hours, mins)
+	case mins > 0:
+		return fmt.Sprintf("%dm %ds", mins, int(d.Seconds())%60)
+	}
+	return fmt.Sprintf("%ds", int(d.Seconds()))
+}
+
+func (m ServiceDetailModel) Title() string {
+	if m.scope == system.ScopeUser {
+		return m.serviceName + " (user)"
+	}
+	return m.serviceName
+}
+
+// HandlesBack is always true so esc can stop live updates before leaving.
+func (m ServiceDetailModel) HandlesBack() bool { return true }
 
 func (m ServiceDetailModel) ShortHelp() []string {
+	if m.running {
+		return nil
+	}
 	if m.actionDone {
 		return []string{"any key continue"}
 	}
@@ -2,13 +2,16 @@ package services
 
 import (
 	"fmt"
+	"slices"
 	"strings"
+	"sync/atomic"
 	"unicode/utf8"
 
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/reisset/mypctools/tui/internal/app"
+	"github.com/reisset/mypctools/tui/internal/config"
 	"github.com/reisset/mypctools/tui/internal/state"
 	"github.com/reisset/mypctools/tui/internal/system"
 	"github.com/reisset/mypctools/tui/internal/theme"
@@ -24,17 +27,37 @@ type menuItem struct {
 // Model is the service manager menu screen.
 type Model struct {
 	shared *state.Shared
+	scope  system.Scope
 	items  []menuItem
 	cursor int
 }
 
 func New(shared *state.Shared) Model {
-	items := []menuItem{
-		{icon: "◎", label: "Common Services", id: "common"},
-		{icon: "◎", label: "All Services", id: "all"},
+	return Model{shared: shared, items: buildItems(system.ScopeSystem), cursor: 0}
+}
+
+func buildItems(scope system.Scope) []menuItem {
+	kind := "Services"
+	if scope == system.ScopeUser {
+		kind = "User Services"
+	}
+	return []menuItem{
+		{icon: "◎", label: "Common " + kind, id: "common"},
+		{icon: "◎", label: "All " + kind, id: "all"},
+		{icon: "⇄", label: "Scope: " + scope.String() + " units", id: "scope"},
 		{icon: "←", label: "Back", id: "back"},
 	}
-	return Model{shared: shared, items: items, cursor: 0}
+}
+
+// toggleScope switches between system and user units.
+func (m Model) toggleScope() Model {
+	if m.scope == system.ScopeUser {
+		m.scope = system.ScopeSystem
+	} else {
+		m.scope = system.ScopeUser
+	}
+	m.items = buildItems(m.scope)
+	return m
 }
 
 func (m Model) Init() tea.Cmd { return nil }
@@ -53,7 +76,12 @@ func (m Model) Update(msg tea.Msg) (app.Screen, tea.Cmd) {
 			if m.cursor < 0 {
 				m.cursor = len(m.items) - 1
 			}
+		case "u":
+			return m.toggleScope(), nil
 		case "enter", " ":
+			if m.items[m.cursor].id == "scope" {
+				return m.toggleScope(), nil
+			}
 			return m, m.handleSelection(m.items[m.cursor].id)
 		}
 	}
@@ -63,15 +91,20 @@ func (m Model) Update(msg tea.Msg) (app.Screen, tea.Cmd) {
 func (m Model) handleSelection(id string) tea.Cmd {
 	switch id {
 	case "common":
-		return app.Navigate(NewServiceList(m.shared, false))
+		return app.Navigate(NewServiceList(m.shared, m.manager(), m.scope, false))
 	case "all":
-		return app.Navigate(NewServiceList(m.shared, true))
+		return app.Navigate(NewServiceList(m.shared, m.manager(), m.scope, true))
 	case "back":
 		return app.PopScreen()
 	}
 	return nil
 }
 
+// manager returns the configured backend for the selected scope.
+func (m Model) manager() system.Manager {
+	return system.NewManager(m.scope, m.shared.Config.Services.Backend)
+}
+
 func (m Model) View() string {
 	width := m.shared.TerminalWidth
 	if width == 0 {
@@ -104,26 +137,63 @@ func (m Model) View() string {
 func (m Model) Title() string     { return "Service Manager" }
 func (m Model) HandlesBack() bool { return false }
 func (m Model) ShortHelp() []string {
-	return []string{"enter select"}
+	return []string{"enter select", "u switch scope"}
 }
 
 // ─── Service List ───────────────────────────────────────────────────────────
 
 // ServiceListModel shows a list of services with their status.
 type ServiceListModel struct {
-	shared   *state.Shared
-	services []system.ServiceStatus
-	cursor   int
-	showAll  bool
-	loading  bool
-	viewport viewport.Model
+	shared    *state.Shared
+	mgr       system.Manager
+	scope     system.Scope
+	services  []system.ServiceStatus // Everything loaded
+	shown     []system.ServiceStatus // services filtered and sorted for display
+	cursor    int                    // Into shown
+	showAll   bool
+	loading   bool
+	streamID  int64                       // Status lookup being received, see serviceStreamMsg
+	stream    <-chan system.ServiceStatus // Its results
+	received  int                         // Statuses received from the current lookup
+	total     int                         // Statuses the current lookup will send
+	filter    quickFilter
+	order     sortOrder
+	search    string
+	searching bool          // Typing the search
+	watch     *system.Watch // Live updates, while the list is on screen
+	pinErr    error         // Saving a pin change failed
+	viewport  viewport.Model
 }
 
 type servicesLoadedMsg struct {
 	services []system.ServiceStatus
 }
 
-func NewServiceList(shared *state.Shared, showAll bool) ServiceListModel {
+// serviceStreamMsg starts a per-unit status lookup, used for All Services
+// when systemctl can't list unit states as JSON.
+type serviceStreamMsg struct {
+	id    int64
+	names []string
+	ch    <-chan system.ServiceStatus
+}
+
+// serviceBatchMsg carries statuses that have arrived from a lookup. ok is
+// false once it has finished.
+type serviceBatchMsg struct {
+	id       int64
+	ch       <-chan system.ServiceStatus
+	statuses []system.ServiceStatus
+	ok       bool
+}
+
+// changesReadMsg carries the statuses of services the watch reported.
+type changesReadMsg struct {
+	statuses []system.ServiceStatus
+}
+
+var nextStreamID atomic.Int64
+
+func NewServiceList(shared *state.Shared, mgr system.Manager, scope system.Scope, showAll bool) ServiceListModel {
 	width := shared.TerminalWidth
 	height := shared.TerminalHeight
 	if width == 0 {
@@ -132,9 +202,11 @@ func NewServiceList(shared *state.Shared, showAll bool) ServiceListModel {
 	if height == 0 {
 		height = 24
 	}
-	vp := viewport.New(width, height-6)
+	vp := viewport.New(width, height-7)
 	return ServiceListModel{
 		shared:   shared,
+		mgr:      mgr,
+		scope:    scope,
 		showAll:  showAll,
 		loading:  true,
 		viewport: vp,
@@ -142,67 +214,319 @@ func NewServiceList(shared *state.Shared, showAll bool) ServiceListModel {
 }
 
 func (m ServiceListModel) Init() tea.Cmd {
-	return m.loadServices()
+	return tea.Batch(m.loadServices(), startWatch(m.mgr))
 }
 
 func (m ServiceListModel) loadServices() tea.Cmd {
+	units := m.units()
+	mgr := m.mgr
 	return func() tea.Msg {
 		if m.showAll {
-			names, err := system.ListAllServices()
+			if svcs, err := mgr.List(); err == nil {
+				return servicesLoadedMsg{services: svcs}
+			}
+			names, err := mgr.Names()
 			if err != nil {
 				return servicesLoadedMsg{services: nil}
 			}
-			var svcs []system.ServiceStatus
-			for _, name := range names {
-				svcs = append(svcs, system.GetServiceStatus(name))
+			return serviceStreamMsg{id: nextStreamID.Add(1), names: names, ch: system.FetchStatuses(mgr, names)}
+		}
+		return servicesLoadedMsg{services: system.GetKnownServices(mgr, units.Known, units.Pinned)}
+	}
+}
+
+// waitStatuses blocks for the next status from a lookup, then also takes
+// whatever else has already arrived.
+func waitStatuses(id int64, ch <-chan system.ServiceStatus) tea.Cmd {
+	return func() tea.Msg {
+		s, ok := <-ch
+		if !ok {
+			return serviceBatchMsg{id: id, ch: ch}
+		}
+		batch := []system.ServiceStatus{s}
+		for len(batch) < 64 {
+			select {
+			case s, ok := <-ch:
+				if !ok {
+					return serviceBatchMsg{id: id, ch: ch, statuses: batch, ok: true}
+				}
+				batch = append(batch, s)
+			default:
+				return serviceBatchMsg{id: id, ch: ch, statuses: batch, ok: true}
 			}
-			return servicesLoadedMsg{services: svcs}
 		}
-		return servicesLoadedMsg{services: system.GetKnownServices()}
+		return serviceBatchMsg{id: id, ch: ch, statuses: batch, ok: true}
+	}
+}
+
+// merge adds or updates statuses in the list.
+func (m *ServiceListModel) merge(statuses []system.ServiceStatus) {
+	for _, s := range statuses {
+		if i := slices.IndexFunc(m.services, func(x system.ServiceStatus) bool { return x.Name == s.Name }); i >= 0 {
+			m.services[i] = s
+		} else {
+			m.services = append(m.services, s)
+		}
+	}
+	m.refresh()
+}
+
+// readChanges re-reads services the watch reported, off the UI thread. All
+// Services also picks up units that appeared; Common Services only updates
+// its own.
+func (m ServiceListModel) readChanges(names []string) tea.Cmd {
+	mgr := m.mgr
+	var wanted []string
+	for _, name := range names {
+		if m.showAll || slices.ContainsFunc(m.services, func(s system.ServiceStatus) bool { return s.Name == name }) {
+			wanted = append(wanted, name)
+		}
+	}
+	if len(wanted) == 0 {
+		return nil
+	}
+	return func() tea.Msg {
+		statuses := make([]system.ServiceStatus, len(wanted))
+		for i, name := range wanted {
+			statuses[i] = mgr.Status(name)
+		}
+		return changesReadMsg{statuses: statuses}
+	}
+}
+
+// applyChanges puts re-read statuses into the list, dropping units that no
+// longer exist.
+func (m *ServiceListModel) applyChanges(statuses []system.ServiceStatus) {
+	for _, status := range statuses {
+		i := slices.IndexFunc(m.services, func(s system.ServiceStatus) bool { return s.Name == status.Name })
+		switch {
+		case !status.Exists && i >= 0:
+			m.services = slices.Delete(m.services, i, i+1)
+		case !status.Exists:
+		case i >= 0:
+			m.services[i] = status
+		case m.showAll:
+			m.services = append(m.services, status)
+		}
+	}
+	m.refresh()
+}
+
+// closeWatch stops live updates.
+func (m *ServiceListModel) closeWatch() {
+	if m.watch != nil {
+		m.watch.Close()
+		m.watch = nil
+	}
+}
+
+// leave stops live updates and abandons any status lookup before the list
+// leaves the screen. Init starts both again on return.
+func (m *ServiceListModel) leave() {
+	m.closeWatch()
+	if m.streamID != 0 {
+		drain(m.stream)
+		m.streamID, m.stream = 0, nil
+	}
+}
+
+// refresh rebuilds the displayed rows from the loaded services: filtered by
+// the quick filter and search, pinned first, then in the chosen order. The
+// cursor stays on the same service when it is still shown.
+func (m *ServiceListModel) refresh() {
+	current := m.selected()
+	units := m.units()
+	shown := make([]system.ServiceStatus, 0, len(m.services))
+	for _, s := range m.services {
+		if m.filter.match(s) && matchSearch(m.search, s) {
+			shown = append(shown, s)
+		}
+	}
+	slices.SortStableFunc(shown, func(a, b system.ServiceStatus) int {
+		if pa, pb := units.IsPinned(a.Name), units.IsPinned(b.Name); pa != pb {
+			if pa {
+				return -1
+			}
+			return 1
+		}
+		return m.order.compare(a, b)
+	})
+	m.shown = shown
+	if i := slices.IndexFunc(m.shown, func(s system.ServiceStatus) bool { return s.Name == current }); i >= 0 {
+		m.cursor = i
+	}
+	m.cursor = min(m.cursor, max(len(m.shown)-1, 0))
+	m.scrollToCursor()
+	m.viewport.SetContent(m.renderRows())
+}
+
+// selected returns the name of the service under the cursor, or "".
+func (m ServiceListModel) selected() string {
+	if m.cursor >= 0 && m.cursor < len(m.shown) {
+		return m.shown[m.cursor].Name
+	}
+	return ""
+}
+
+// units returns the configured services for the list's scope.
+func (m ServiceListModel) units() config.Units {
+	return *m.shared.Config.Services.Scope(m.scope == system.ScopeUser)
+}
+
+// togglePin pins or unpins the service under the cursor and saves the
+// config, keeping the cursor on that service as the list re-sorts.
+func (m ServiceListModel) togglePin() ServiceListModel {
+	cfg := m.shared.Config
+	cfg.Services.Scope(m.scope == system.ScopeUser).TogglePin(m.selected())
+	if err := config.Save(cfg); err != nil {
+		m.pinErr = err
+		return m
 	}
+	m.pinErr = nil
+	m.shared.Config = cfg
+	m.refresh()
+	return m
 }
 
 func (m ServiceListModel) Update(msg tea.Msg) (app.Screen, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
-		m.viewport = viewport.New(msg.Width, msg.Height-6)
-		m.viewport.SetContent(m.renderRows())
+		m.viewport = viewport.New(msg.Width, msg.Height-7)
+		m.refresh()
 		return m, nil
 
 	case servicesLoadedMsg:
 		m.services = msg.services
 		m.loading = false
-		m.viewport.SetContent(m.renderRows())
+		m.refresh()
 		return m, nil
 
+	case serviceStreamMsg:
+		if m.stream != nil {
+			drain(m.stream) // Replaced before it finished
+		}
+		m.streamID, m.stream, m.received, m.total = msg.id, msg.ch, 0, len(msg.names)
+		// Coming back from a detail screen refreshes the list in place; drop
+		// only units that no longer exist.
+		m.services = slices.DeleteFunc(m.services, func(s system.ServiceStatus) bool {
+			return !slices.Contains(msg.names, s.Name)
+		})
+		m.refresh()
+		return m, waitStatuses(msg.id, msg.ch)
+
+	case watchStartedMsg:
+		m.closeWatch()
+		m.watch = msg.watch
+		return m, waitChanges(m.watch)
+
+	case unitsChangedMsg:
+		if m.watch == nil || msg.id != m.watch.ID {
+			return m, nil
+		}
+		return m, tea.Batch(m.readChanges(msg.names), waitChanges(m.watch))
+
+	case changesReadMsg:
+		m.applyChanges(msg.statuses)
+		return m, nil
+
+	case serviceBatchMsg:
+		if msg.id != m.streamID {
+			drain(msg.ch)
+			return m, nil
+		}
+		if !msg.ok {
+			m.streamID, m.stream = 0, nil
+			m.loading = false
+			return m, nil
+		}
+		m.received += len(msg.statuses)
+		m.merge(msg.statuses)
+		return m, waitStatuses(msg.id, msg.ch)
+
 	case tea.KeyMsg:
-		if m.loading {
+		if m.searching {
+			return m.updateSearch(msg)
+		}
+		if msg.String() == "esc" {
+			m.leave()
+			return m, app.PopScreen()
+		}
+		if len(m.services) == 0 {
 			return m, nil
 		}
 		switch msg.String() {
-		case "down":
-			m.cursor++
-			if m.cursor >= len(m.services) {
-				m.cursor = 0
-			}
-			m.scrollToCursor()
-			m.viewport.SetContent(m.renderRows())
-		case "up":
-			m.cursor--
-			if m.cursor < 0 {
-				m.cursor = len(m.services) - 1
-			}
-			m.scrollToCursor()
-			m.viewport.SetContent(m.renderRows())
+		case "down", "up":
+			m.move(msg.String())
+		case "/":
+			m.searching = true
+		case "f":
+			m.filter = (m.filter + 1) % filterCount
+			m.refresh()
+		case "s":
+			m.order = (m.order + 1) % sortCount
+			m.refresh()
 		case "enter", " ":
-			if len(m.services) > 0 && m.cursor >= 0 && m.cursor < len(m.services) {
-				return m, app.Navigate(NewServiceDetail(m.shared, m.services[m.cursor].Name))
+			if name := m.selected(); name != "" {
+				m.leave()
+				return m, app.Navigate(NewServiceDetail(m.shared, m.mgr, m.scope, name))
+			}
+		case "p":
+			if m.selected() != "" {
+				return m.togglePin(), nil
 			}
 		}
 	}
 	return m, nil
 }
 
+// updateSearch edits the search as it is typed. Enter keeps it, esc clears
+// it; the arrow keys still move through the matches.
+func (m ServiceListModel) updateSearch(msg tea.KeyMsg) (app.Screen, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		m.searching = false
+		return m, nil
+	case tea.KeyEsc:
+		m.searching = false
+		m.search = ""
+	case tea.KeyUp, tea.KeyDown:
+		m.move(msg.String())
+		return m, nil
+	case tea.KeyBackspace:
+		if r := []rune(m.search); len(r) > 0 {
+			m.search = string(r[:len(r)-1])
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		m.search += string(msg.Runes)
+	default:
+		return m, nil
+	}
+	m.refresh()
+	return m, nil
+}
+
+// move steps the cursor up or down, wrapping at either end.
+func (m *ServiceListModel) move(dir string) {
+	if len(m.shown) == 0 {
+		return
+	}
+	if dir == "down" {
+		m.cursor = (m.cursor + 1) % len(m.shown)
+	} else {
+		m.cursor = (m.cursor + len(m.shown) - 1) % len(m.shown)
+	}
+	m.scrollToCursor()
+	m.viewport.SetContent(m.renderRows())
+}
+
+// drain empties a lookup nobody is listening to, so its workers can exit.
+func drain(ch <-chan system.ServiceStatus) {
+	go func() {
+		for range ch {
+		}
+	}()
+}
+
 func (m *ServiceListModel) scrollToCursor() {
 	visH := m.viewport.Height
 	if visH <= 0 {
@@ -218,7 +542,7 @@ func (m *ServiceListModel) scrollToCursor() {
 }
 
 func (m ServiceListModel) renderRows() string {
-	if len(m.services) == 0 {
+	if len(m.shown) == 0 {
 		return ""
 	}
 
@@ -228,10 +552,15 @@ func (m ServiceListModel) renderRows() string {
 		Foreground(lipgloss.Color("#ffffff")).
 		Bold(true)
 
+	pins := m.units()
 	var rows []string
-	for i, svc := range m.services {
+	for i, svc := range m.shown {
 		name := truncate(svc.Name, 26)
 		nameCol := fmt.Sprintf("%-26s", name)
+		pinCol := "  "
+		if pins.IsPinned(svc.Name) {
+			pinCol = "★ "
+		}
 
 		var statusCol string
 		switch svc.Active {
@@ -243,7 +572,12 @@ func (m ServiceListModel) renderRows() string {
 			statusCol = theme.MutedStyle().Render("○ " + truncate(svc.Active, 4))
 		}
 
-		row := nameCol + "  " + statusCol
+		memCol := fmt.Sprintf("%8s", "")
+		if svc.Memory >= 0 {
+			memCol = fmt.Sprintf("%8s", system.FormatSize(svc.Memory))
+		}
+
+		row := pinCol + nameCol + "  " + statusCol + "  " + memCol
 
 		if i == m.cursor {
 			content := selectedBg.Render("  " + row + "  ")
@@ -261,49 +595,68 @@ func (m ServiceListModel) View() string {
 	if width == 0 {
 		width = 80
 	}
+	center := func(s string) string {
+		return lipgloss.NewStyle().Width(width).Align(lipgloss.Center).Render(s)
+	}
 
-	if m.loading {
-		loading := theme.MutedStyle().Render("Loading services...")
-		return lipgloss.NewStyle().Width(width).Align(lipgloss.Center).Render(loading)
+	if m.loading && len(m.services) == 0 {
+		return center(theme.MutedStyle().Render("Loading services..."))
 	}
 
 	if len(m.services) == 0 {
-		msg := theme.WarningStyle().Render("No services found")
-		return lipgloss.NewStyle().Width(width).Align(lipgloss.Center).Render(msg)
+		return center(theme.WarningStyle().Render("No services found"))
+	}
+
+	// Active filter, search and sort
+	view := []string{"show: " + m.filter.String(), "sort: " + m.order.String()}
+	if m.searching {
+		view = append(view, "search: "+m.search+"▏")
+	} else if m.search != "" {
+		view = append(view, "search: "+m.search)
 	}
+	viewLine := theme.MutedStyle().Render(strings.Join(view, "  ·  "))
 
 	// Column header
 	headerContent := lipgloss.NewStyle().
 		Foreground(lipgloss.Color(theme.Current.Muted)).
-		Render(fmt.Sprintf("   %-26s  %s", "SERVICE", "STATUS"))
+		Render(fmt.Sprintf("     %-26s  %-6s  %8s", "SERVICE", "STATUS", "MEMORY"))
 
-	// Scroll count hint
+	// Count hint, over the filtered rows
 	var countHint string
-	if len(m.services) > m.viewport.Height {
-		countHint = "  " + theme.MutedStyle().Render(fmt.Sprintf("[%d/%d]", m.cursor+1, len(m.services)))
+	if len(m.shown) > m.viewport.Height || len(m.shown) < len(m.services) {
+		countHint = "  " + theme.MutedStyle().Render(fmt.Sprintf("[%d/%d]", min(m.cursor+1, len(m.shown)), len(m.shown)))
 	}
 
-	sepContent := "   " + theme.HelpDividerStyle().Render(strings.Repeat("─", 36))
+	sepContent := "   " + theme.HelpDividerStyle().Render(strings.Repeat("─", 48))
 
-	headerBlock := lipgloss.NewStyle().Width(width).Align(lipgloss.Center).Render(headerContent + countHint)
-	sepBlock := lipgloss.NewStyle().Width(width).Align(lipgloss.Center).Render(sepContent)
-	viewportBlock := lipgloss.NewStyle().Width(width).Align(lipgloss.Center).Render(m.viewport.View())
+	body := center(m.viewport.View())
+	if len(m.shown) == 0 {
+		body = center(theme.MutedStyle().Render("No services match"))
+	}
 
-	return lipgloss.JoinVertical(lipgloss.Left,
-		"",
-		headerBlock,
-		sepBlock,
-		viewportBlock,
-	)
+	parts := []string{"", center(viewLine), center(headerContent + countHint), center(sepContent), body}
+	if m.streamID != 0 {
+		progress := theme.MutedStyle().Render(fmt.Sprintf("Loading services… %d/%d", m.received, m.total))
+		parts = append(parts, center(progress))
+	}
+	if m.pinErr != nil {
+		parts = append(parts, center(theme.WarningStyle().Render("⚠  Could not save pin: "+m.pinErr.Error())))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, parts...)
 }
 
-func (m ServiceListModel) HandlesBack() bool { return false }
+// HandlesBack is always true so esc can clear the search, or stop live
+// updates before leaving.
+func (m ServiceListModel) HandlesBack() bool { return true }
 
 func (m ServiceListModel) Title() string {
 	label := "Common Services"
 	if m.showAll {
 		label = "All Services"
 	}
+	if m.scope == system.ScopeUser {
+		label = strings.Replace(label, "Services", "User Services", 1)
+	}
 	if !m.loading && len(m.services) > 0 {
 		return fmt.Sprintf("%s (%d)", label, len(m.services))
 	}
@@ -311,7 +664,14 @@ func (m ServiceListModel) Title() string {
 }
 
 func (m ServiceListModel) ShortHelp() []string {
-	return []string{"↑↓ navigate", "enter details"}
+	if m.searching {
+		return []string{"↑↓ navigate", "enter done", "esc clear"}
+	}
+	pin := "p pin"
+	if name := m.selected(); name != "" && m.units().IsPinned(name) {
+		pin = "p unpin"
+	}
+	return []string{"↑↓ navigate", "enter details", pin, "/ search", "f filter", "s sort"}
 }
 
 func truncate(s string, max int) string {
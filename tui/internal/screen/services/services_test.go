@@ -0,0 +1,200 @@
+package services
+
+import (
+	"slices"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/reisset/mypctools/tui/internal/app"
+	"github.com/reisset/mypctools/tui/internal/config"
+	"github.com/reisset/mypctools/tui/internal/state"
+	"github.com/reisset/mypctools/tui/internal/system"
+	"github.com/reisset/mypctools/tui/internal/system/systemtest"
+)
+
+func service(name, active string) system.ServiceStatus {
+	sub := "dead"
+	if active == "active" {
+		sub = "running"
+	}
+	return system.ServiceStatus{Name: name, Active: active, SubState: sub, Enabled: "disabled", Memory: -1, CPU: -1, Tasks: -1}
+}
+
+// run runs cmd and any commands it batches, returning their messages.
+func run(cmd tea.Cmd) []tea.Msg {
+	if cmd == nil {
+		return nil
+	}
+	msg := cmd()
+	batch, ok := msg.(tea.BatchMsg)
+	if !ok {
+		return []tea.Msg{msg}
+	}
+	var msgs []tea.Msg
+	for _, c := range batch {
+		msgs = append(msgs, run(c)...)
+	}
+	return msgs
+}
+
+// split separates the commands cmd batches, so a test can run the ones that
+// return at once and hold on to the one waiting for the watch. The waiting
+// one is last.
+func split(t *testing.T, cmd tea.Cmd) (now []tea.Cmd, wait tea.Cmd) {
+	t.Helper()
+	batch, ok := cmd().(tea.BatchMsg)
+	if !ok || len(batch) == 0 {
+		t.Fatalf("expected a batch of commands")
+	}
+	return batch[:len(batch)-1], batch[len(batch)-1]
+}
+
+// feed sends msgs to screen, returning it and the commands it asked for.
+func feed(t *testing.T, screen app.Screen, msgs ...tea.Msg) (app.Screen, []tea.Cmd) {
+	t.Helper()
+	var cmds []tea.Cmd
+	for _, msg := range msgs {
+		var cmd tea.Cmd
+		screen, cmd = screen.Update(msg)
+		if cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+	}
+	return screen, cmds
+}
+
+func names(statuses []system.ServiceStatus) []string {
+	var names []string
+	for _, s := range statuses {
+		names = append(names, s.Name)
+	}
+	return names
+}
+
+func testShared() *state.Shared {
+	return &state.Shared{TerminalWidth: 80, TerminalHeight: 24, Config: config.Default()}
+}
+
+func TestServiceListWatch(t *testing.T) {
+	fake := systemtest.NewFake(service("sshd", "active"), service("cups", "inactive"))
+	var screen app.Screen = NewServiceList(testShared(), fake, system.ScopeSystem, true)
+	screen, cmds := feed(t, screen, run(screen.Init())...)
+	list := screen.(ServiceListModel)
+	if got := names(list.services); !slices.Equal(got, []string{"cups", "sshd"}) {
+		t.Fatalf("loaded %v, want [cups sshd]", got)
+	}
+	if list.watch == nil || len(cmds) != 1 {
+		t.Fatalf("watch not started: %v, %d commands", list.watch, len(cmds))
+	}
+
+	// A unit starts and another appears; both arrive in one update.
+	fake.Set(service("bluetooth", "inactive"))
+	if _, err := fake.Action("cups", "start"); err != nil {
+		t.Fatal(err)
+	}
+	msgs := run(cmds[0])
+	if changed, ok := msgs[0].(unitsChangedMsg); !ok || len(changed.names) != 2 {
+		t.Fatalf("got %#v, want both changes", msgs[0])
+	}
+	screen, cmds = feed(t, screen, msgs...)
+	if len(cmds) != 1 {
+		t.Fatalf("list stopped waiting for changes")
+	}
+	read, _ := split(t, cmds[0])
+	if screen.(ServiceListModel).services[0].Active != "inactive" {
+		t.Error("statuses read in Update rather than a command")
+	}
+	screen, _ = feed(t, screen, run(read[0])...)
+	list = screen.(ServiceListModel)
+	if got := names(list.shown); !slices.Equal(got, []string{"bluetooth", "cups", "sshd"}) {
+		t.Errorf("shown %v, want [bluetooth cups sshd]", got)
+	}
+	if i := slices.IndexFunc(list.services, func(s system.ServiceStatus) bool { return s.Name == "cups" }); list.services[i].Active != "active" {
+		t.Errorf("cups is %s, want active", list.services[i].Active)
+	}
+
+	// Leaving closes the watch.
+	w := list.watch
+	screen, cmds = feed(t, screen, tea.KeyMsg{Type: tea.KeyEsc})
+	if msgs := run(cmds[0]); len(msgs) != 1 || msgs[0] != (app.PopScreenMsg{}) {
+		t.Errorf("esc sent %v, want PopScreenMsg", msgs)
+	}
+	if screen.(ServiceListModel).watch != nil {
+		t.Error("watch kept after leaving")
+	}
+	if msg := run(waitChanges(w)); msg[0] != nil {
+		t.Errorf("closed watch delivered %#v", msg[0])
+	}
+}
+
+func TestServiceListCommonIgnoresOtherUnits(t *testing.T) {
+	fake := systemtest.NewFake(service("sshd", "active"), service("cups", "inactive"), service("foo", "inactive"))
+	var screen app.Screen = NewServiceList(testShared(), fake, system.ScopeSystem, false)
+	screen, cmds := feed(t, screen, run(screen.Init())...)
+	if got := names(screen.(ServiceListModel).services); !slices.Equal(got, []string{"sshd", "cups"}) {
+		t.Fatalf("loaded %v, want [sshd cups]", got)
+	}
+
+	fake.Set(service("foo", "active"))
+	fake.Set(service("sshd", "failed"))
+	screen, cmds = feed(t, screen, run(cmds[0])...)
+	read, _ := split(t, cmds[0])
+	screen, _ = feed(t, screen, run(read[0])...)
+	list := screen.(ServiceListModel)
+	if got := names(list.services); !slices.Equal(got, []string{"sshd", "cups"}) {
+		t.Errorf("services %v, want [sshd cups]", got)
+	}
+	if list.services[0].Active != "failed" {
+		t.Errorf("sshd is %s, want failed", list.services[0].Active)
+	}
+	list.closeWatch()
+}
+
+func TestServiceDetailAction(t *testing.T) {
+	t.Setenv("HOME", t.TempDir()) // The action is logged
+	fake := systemtest.NewFake(service("cups", "inactive"))
+	var screen app.Screen = NewServiceDetail(testShared(), fake, system.ScopeSystem, "cups")
+	screen, cmds := feed(t, screen, run(screen.Init())...)
+	if len(cmds) != 1 {
+		t.Fatal("watch not started")
+	}
+	watching := cmds[0]
+
+	detail := screen.(ServiceDetailModel)
+	if detail.items[0].action != actionStart {
+		t.Fatalf("first action is %d, want start", detail.items[0].action)
+	}
+	screen, cmds = feed(t, screen, tea.KeyMsg{Type: tea.KeyEnter})
+	if !screen.(ServiceDetailModel).running {
+		t.Error("not running after enter")
+	}
+	screen, cmds = feed(t, screen, run(cmds[0])...)
+	screen, _ = feed(t, screen, run(cmds[0])...) // The status read after it
+	detail = screen.(ServiceDetailModel)
+	if !slices.Equal(fake.Actions, []string{"start cups"}) {
+		t.Errorf("actions %v, want [start cups]", fake.Actions)
+	}
+	if !detail.actionDone || detail.actionErr != nil || detail.status.Active != "active" {
+		t.Errorf("after start: done=%v err=%v active=%s", detail.actionDone, detail.actionErr, detail.status.Active)
+	}
+	if detail.items[0].action != actionStop {
+		t.Errorf("first action is %d, want stop", detail.items[0].action)
+	}
+
+	// A change made elsewhere shows up live.
+	fake.Set(service("cups", "failed"))
+	screen, cmds = feed(t, screen, run(watching)...)
+	read, _ := split(t, cmds[0])
+	screen, _ = feed(t, screen, run(read[0])...)
+	if got := screen.(ServiceDetailModel).status.Active; got != "failed" {
+		t.Errorf("cups is %s, want failed", got)
+	}
+
+	screen, cmds = feed(t, screen, tea.KeyMsg{Type: tea.KeyEsc})
+	if msgs := run(cmds[0]); len(msgs) != 1 || msgs[0] != (app.PopScreenMsg{}) {
+		t.Errorf("esc sent %v, want PopScreenMsg", msgs)
+	}
+	if screen.(ServiceDetailModel).watch != nil {
+		t.Error("watch kept after leaving")
+	}
+}
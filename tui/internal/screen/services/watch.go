@@ -0,0 +1,57 @@
+package services
+
+import (
+	"slices"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/reisset/mypctools/tui/internal/system"
+)
+
+// Live updates: a screen starts a watch in Init and closes it before it
+// leaves or covers itself with another screen, so only the screen on top is
+// listening. Backends without watch support just don't update live.
+
+type watchStartedMsg struct {
+	watch *system.Watch
+}
+
+// unitsChangedMsg names services whose state changed.
+type unitsChangedMsg struct {
+	id    int64
+	names []string
+}
+
+func startWatch(mgr system.Manager) tea.Cmd {
+	return func() tea.Msg {
+		w, err := mgr.Watch()
+		if err != nil {
+			return nil
+		}
+		return watchStartedMsg{watch: w}
+	}
+}
+
+// waitChanges blocks for the next change, then also takes any others that
+// have already arrived, since one restart changes a unit several times.
+func waitChanges(w *system.Watch) tea.Cmd {
+	return func() tea.Msg {
+		name, ok := <-w.C
+		if !ok {
+			return nil
+		}
+		names := []string{name}
+		for {
+			select {
+			case name, ok := <-w.C:
+				if !ok {
+					return unitsChangedMsg{id: w.ID, names: names}
+				}
+				if !slices.Contains(names, name) {
+					names = append(names, name)
+				}
+			default:
+				return unitsChangedMsg{id: w.ID, names: names}
+			}
+		}
+	}
+}
@@ -0,0 +1,285 @@
+package settings
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+	"github.com/reisset/mypctools/tui/internal/app"
+	"github.com/reisset/mypctools/tui/internal/config"
+	"github.com/reisset/mypctools/tui/internal/logging"
+	"github.com/reisset/mypctools/tui/internal/runner"
+	"github.com/reisset/mypctools/tui/internal/state"
+	"github.com/reisset/mypctools/tui/internal/theme"
+	"github.com/reisset/mypctools/tui/internal/ui"
+)
+
+type field int
+
+const (
+	fieldIcons field = iota
+	fieldCapture
+	fieldToast
+	fieldTheme
+	fieldRemote
+	fieldBranch
+	fieldServices
+	fieldUserServices
+	fieldBackend
+	fieldCount
+)
+
+// valueWidth caps how much of a setting's value the list shows.
+const valueWidth = 30
+
+// Model edits config.toml. Every change is validated and saved immediately.
+type Model struct {
+	shared  *state.Shared
+	cursor  int
+	editing bool   // Typing a new value for a text field
+	input   []rune // Text being typed
+	err     error  // Last rejected change
+}
+
+func New(shared *state.Shared) Model {
+	return Model{shared: shared}
+}
+
+// Apply puts settings that live outside the config struct into effect.
+// Called at startup and after every saved change.
+func Apply(cfg config.File) {
+	theme.SetNerdIcons(cfg.UI.NerdFont)
+	runner.SetCaptureEnabled(cfg.UI.CaptureOutput)
+	if cfg.Terminal.Theme != "" {
+		os.Setenv(config.TerminalThemeEnv, cfg.Terminal.Theme)
+	} else {
+		os.Unsetenv(config.TerminalThemeEnv)
+	}
+}
+
+func (m Model) Init() tea.Cmd { return nil }
+
+func (m Model) Update(msg tea.Msg) (app.Screen, tea.Cmd) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	if m.editing {
+		return m.updateEditing(key)
+	}
+
+	cfg := m.shared.Config
+	f := field(m.cursor)
+	switch key.String() {
+	case "down":
+		m.cursor = (m.cursor + 1) % int(fieldCount)
+	case "up":
+		m.cursor = (m.cursor + int(fieldCount) - 1) % int(fieldCount)
+	case "right", "l":
+		return m.adjust(cfg, f, 1)
+	case "left", "h":
+		return m.adjust(cfg, f, -1)
+	case "enter", " ":
+		switch f {
+		case fieldRemote, fieldBranch, fieldServices, fieldUserServices:
+			m.editing = true
+			m.input = []rune(m.value(f))
+			m.err = nil
+			return m, nil
+		}
+		return m.adjust(cfg, f, 1)
+	}
+	return m, nil
+}
+
+// adjust toggles or steps the setting under the cursor by dir.
+func (m Model) adjust(cfg config.File, f field, dir int) (app.Screen, tea.Cmd) {
+	switch f {
+	case fieldIcons:
+		cfg.UI.NerdFont = !cfg.UI.NerdFont
+	case fieldCapture:
+		cfg.UI.CaptureOutput = !cfg.UI.CaptureOutput
+	case fieldToast:
+		cfg.UI.ToastSeconds = min(max(cfg.UI.ToastSeconds+dir, 1), 10)
+	case fieldTheme:
+		n := len(config.TerminalThemes)
+		i := slices.Index(config.TerminalThemes, cfg.Terminal.Theme)
+		cfg.Terminal.Theme = config.TerminalThemes[(i+dir+n)%n]
+	case fieldBackend:
+		n := len(config.ServiceBackends)
+		i := slices.Index(config.ServiceBackends, cfg.Services.Backend)
+		cfg.Services.Backend = config.ServiceBackends[(i+dir+n)%n]
+	default:
+		return m, nil
+	}
+	return m.save(cfg)
+}
+
+func (m Model) updateEditing(key tea.KeyMsg) (app.Screen, tea.Cmd) {
+	switch key.Type {
+	case tea.KeyEsc:
+		m.editing = false
+	case tea.KeyEnter:
+		cfg := m.shared.Config
+		text := strings.TrimSpace(string(m.input))
+		switch field(m.cursor) {
+		case fieldRemote:
+			cfg.Update.Remote = text
+		case fieldBranch:
+			cfg.Update.Branch = text
+		case fieldServices:
+			cfg.Services.Known = splitList(text)
+		case fieldUserServices:
+			cfg.Services.User.Known = splitList(text)
+		}
+		m.editing = false
+		return m.save(cfg)
+	case tea.KeyBackspace:
+		if len(m.input) > 0 {
+			m.input = m.input[:len(m.input)-1]
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		m.input = append(m.input, key.Runes...)
+	}
+	return m, nil
+}
+
+// splitList splits a comma- or space-separated list.
+func splitList(text string) []string {
+	return strings.FieldsFunc(text, func(r rune) bool { return r == ',' || r == ' ' })
+}
+
+// save validates and writes cfg, keeping the old settings if it is rejected.
+func (m Model) save(cfg config.File) (app.Screen, tea.Cmd) {
+	if err := config.Save(cfg); err != nil {
+		m.err = err
+		return m, nil
+	}
+	m.err = nil
+	m.shared.Config = cfg
+	Apply(cfg)
+	logging.LogAction(fmt.Sprintf("Settings: %s = %s", m.label(field(m.cursor)), m.value(field(m.cursor)))) //nolint:errcheck
+	return m, nil
+}
+
+func (m Model) label(f field) string {
+	return [...]string{
+		fieldIcons:        "Nerd Font icons",
+		fieldCapture:      "Output capture",
+		fieldToast:        "Toast duration",
+		fieldTheme:        "Terminal theme",
+		fieldRemote:       "Update remote",
+		fieldBranch:       "Update branch",
+		fieldServices:     "Known services",
+		fieldUserServices: "Known user services",
+		fieldBackend:      "Service backend",
+	}[f]
+}
+
+func (m Model) description(f field) string {
+	return [...]string{
+		fieldIcons:        "needs a Nerd Font in your terminal",
+		fieldCapture:      "show command output in a scrollable log",
+		fieldToast:        "how long notifications stay on screen",
+		fieldTheme:        "used by the kitty and alacritty bundles",
+		fieldRemote:       "git remote Pull Updates fetches from",
+		fieldBranch:       "branch Pull Updates follows",
+		fieldServices:     "system units listed by the Service Manager",
+		fieldUserServices: "user units listed by the Service Manager",
+		fieldBackend:      "auto uses D-Bus for live status, else systemctl",
+	}[f]
+}
+
+func (m Model) value(f field) string {
+	cfg := m.shared.Config
+	onOff := func(b bool) string {
+		if b {
+			return "on"
+		}
+		return "off"
+	}
+	switch f {
+	case fieldIcons:
+		return onOff(cfg.UI.NerdFont)
+	case fieldCapture:
+		return onOff(cfg.UI.CaptureOutput)
+	case fieldToast:
+		return fmt.Sprintf("%ds", cfg.UI.ToastSeconds)
+	case fieldTheme:
+		if cfg.Terminal.Theme == "" {
+			return "ask on install"
+		}
+		return cfg.Terminal.Theme
+	case fieldRemote:
+		return cfg.Update.Remote
+	case fieldBranch:
+		return cfg.Update.Branch
+	case fieldServices:
+		return strings.Join(cfg.Services.Known, ", ")
+	case fieldUserServices:
+		return strings.Join(cfg.Services.User.Known, ", ")
+	case fieldBackend:
+		return cfg.Services.Backend
+	}
+	return ""
+}
+
+func (m Model) View() string {
+	width := m.shared.TerminalWidth
+	if width == 0 {
+		width = 80
+	}
+	center := func(s string) string {
+		return lipgloss.NewStyle().Width(width).Align(lipgloss.Center).Render(s)
+	}
+
+	items := make([]ui.ListItem, fieldCount)
+	for i := range items {
+		f := field(i)
+		value := runewidth.Truncate(m.value(f), valueWidth, "…")
+		suffix := lipgloss.NewStyle().Foreground(lipgloss.Color(theme.Current.Primary)).Render(value)
+		if m.editing && i == m.cursor {
+			text := string(m.input)
+			if over := runewidth.StringWidth(text) - valueWidth + 1; over > 0 {
+				text = runewidth.TruncateLeft(text, over+1, "…") // Keep the end in view
+			}
+			suffix = lipgloss.NewStyle().Foreground(lipgloss.Color("#ffffff")).Underline(true).Render(text + "▏")
+		}
+		items[i] = ui.ListItem{
+			Label:       m.label(f),
+			Suffix:      suffix,
+			Description: m.description(f),
+		}
+	}
+	list := ui.RenderList(items, m.cursor, ui.ListConfig{
+		Width:         64,
+		MaxInnerWidth: 64,
+		Height:        max((m.shared.ContentHeight-4)/2, 2),
+	})
+
+	path, _ := config.Path()
+	status := theme.MutedStyle().Render("Saved to " + strings.Replace(path, os.Getenv("HOME"), "~", 1))
+	if m.err != nil {
+		status = theme.WarningStyle().Render("⚠  " + strings.ReplaceAll(m.err.Error(), "\n", "; "))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, center(list), "", center(status))
+}
+
+func (m Model) Title() string { return "Settings" }
+
+func (m Model) HandlesBack() bool { return m.editing }
+
+func (m Model) ShortHelp() []string {
+	if m.editing {
+		return []string{"enter save", "esc cancel"}
+	}
+	switch field(m.cursor) {
+	case fieldRemote, fieldBranch, fieldServices, fieldUserServices:
+		return []string{"↑↓ navigate", "enter edit", "esc back"}
+	}
+	return []string{"↑↓ navigate", "←→ change", "esc back"}
+}
@@ -0,0 +1,223 @@
+package snapshots
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/reisset/mypctools/tui/internal/app"
+	"github.com/reisset/mypctools/tui/internal/fssnap"
+	"github.com/reisset/mypctools/tui/internal/logging"
+	"github.com/reisset/mypctools/tui/internal/runner"
+	"github.com/reisset/mypctools/tui/internal/state"
+	"github.com/reisset/mypctools/tui/internal/theme"
+	"github.com/reisset/mypctools/tui/internal/ui"
+)
+
+type loadedMsg struct {
+	entries []fssnap.Entry
+	err     error
+}
+
+// Model lists the filesystem snapshots mypctools took before updates.
+type Model struct {
+	shared     *state.Shared
+	entries    []fssnap.Entry
+	cursor     int
+	loaded     bool
+	err        error
+	confirming bool
+	deleting   *fssnap.Entry // Snapshot whose delete command is running
+	status     string
+	statusErr  bool
+	pane       runner.Pane
+}
+
+// New creates a snapshots screen.
+func New(shared *state.Shared) Model {
+	return Model{shared: shared, pane: runner.NewPane(shared)}
+}
+
+func (m Model) Init() tea.Cmd {
+	return func() tea.Msg {
+		entries, err := fssnap.List()
+		return loadedMsg{entries: entries, err: err}
+	}
+}
+
+func (m Model) Update(msg tea.Msg) (app.Screen, tea.Cmd) {
+	pane, cmd, handled := m.pane.Update(msg)
+	m.pane = pane
+	if handled {
+		return m, cmd
+	}
+
+	switch msg := msg.(type) {
+	case loadedMsg:
+		m.loaded = true
+		m.entries = msg.entries
+		m.err = msg.err
+		if m.cursor >= len(m.entries) {
+			m.cursor = max(len(m.entries)-1, 0)
+		}
+		return m, nil
+
+	case app.ExecDoneMsg:
+		e := m.deleting
+		m.deleting = nil
+		if e == nil {
+			return m, nil
+		}
+		if msg.Err != nil {
+			m.status = fmt.Sprintf("Delete failed: %v", msg.Err)
+			m.statusErr = true
+			return m, nil
+		}
+		if err := fssnap.Forget(*e); err != nil {
+			m.status = fmt.Sprintf("Deleted, but the list could not be updated: %v", err)
+			m.statusErr = true
+		} else {
+			m.status = fmt.Sprintf("Deleted %s snapshot %s", e.Tool, e.ID)
+			m.statusErr = false
+		}
+		logging.LogAction(fmt.Sprintf("Deleted %s snapshot %s", e.Tool, e.ID)) //nolint:errcheck
+		return m, m.Init()
+
+	case tea.KeyMsg:
+		if m.confirming {
+			switch msg.String() {
+			case "y", "Y":
+				m.confirming = false
+				return m.remove(m.entries[m.cursor])
+			case "n", "N", "esc":
+				m.confirming = false
+			}
+			return m, nil
+		}
+		if !m.loaded || len(m.entries) == 0 {
+			return m, nil
+		}
+
+		switch msg.String() {
+		case "down":
+			m.cursor++
+			if m.cursor >= len(m.entries) {
+				m.cursor = 0
+			}
+		case "up":
+			m.cursor--
+			if m.cursor < 0 {
+				m.cursor = len(m.entries) - 1
+			}
+		case "x", "delete":
+			m.confirming = true
+			m.status = ""
+		}
+	}
+	return m, nil
+}
+
+// remove runs the tool's delete command in the pane (it needs sudo).
+func (m Model) remove(e fssnap.Entry) (app.Screen, tea.Cmd) {
+	tool, ok := fssnap.ByName(e.Tool)
+	if !ok {
+		m.status = "Unknown snapshot tool: " + e.Tool
+		m.statusErr = true
+		return m, nil
+	}
+	m.deleting = &e
+	return m, m.pane.Exec(tool.Delete(e.ID), "delete "+e.Tool+" snapshot")
+}
+
+func (m Model) View() string {
+	if m.pane.Running() {
+		return m.pane.View("Deleting snapshot")
+	}
+
+	width := m.shared.TerminalWidth
+	if width == 0 {
+		width = 80
+	}
+	center := func(s string) string {
+		return lipgloss.NewStyle().Width(width).Align(lipgloss.Center).Render(s)
+	}
+
+	if !m.loaded {
+		return center(theme.MutedStyle().Render("Loading snapshots..."))
+	}
+	if m.err != nil {
+		return center(theme.ErrorStyle().Render("Could not read snapshots: " + m.err.Error()))
+	}
+	if len(m.entries) == 0 {
+		parts := []string{}
+		if m.status != "" {
+			parts = append(parts, center(m.renderStatus()), "")
+		}
+		hint := "No snapshots yet — Full System Update takes one before upgrading"
+		if m.shared.Snap == nil {
+			hint = "No snapshots — install snapper or timeshift, or use btrfs for /"
+		}
+		parts = append(parts, center(theme.MutedStyle().Render(hint)))
+		return lipgloss.JoinVertical(lipgloss.Left, parts...)
+	}
+
+	items := make([]ui.ListItem, len(m.entries))
+	for i, e := range m.entries {
+		items[i] = ui.ListItem{
+			Icon:        "◷",
+			Label:       e.Time.Format("2006-01-02 15:04"),
+			Suffix:      theme.MutedStyle().Render(e.Tool + " " + e.ID),
+			Description: e.Description,
+		}
+	}
+	height := (m.shared.ContentHeight - 5) / 2 // Two lines per item
+	list := ui.RenderList(items, m.cursor, ui.ListConfig{
+		Width:         72,
+		MaxInnerWidth: 72,
+		Height:        max(height, 2),
+	})
+
+	parts := []string{center(list), ""}
+	e := m.entries[m.cursor]
+	if m.confirming {
+		parts = append(parts,
+			center(theme.WarningStyle().Render(fmt.Sprintf("Delete %s snapshot %s?", e.Tool, e.ID))),
+			center(theme.MutedStyle().Render("this cannot be undone · y confirm · n cancel")))
+	} else {
+		if hint := fssnap.RestoreHint(e); hint != "" {
+			parts = append(parts, center(theme.MutedStyle().Render("To roll back: "+hint)))
+		}
+		if m.status != "" {
+			parts = append(parts, center(m.renderStatus()))
+		}
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, parts...)
+}
+
+func (m Model) renderStatus() string {
+	if m.statusErr {
+		return theme.ErrorStyle().Render(m.status)
+	}
+	icons := theme.GetIcons()
+	return theme.SuccessStyle().Render(icons.Check + " " + m.status)
+}
+
+func (m Model) Title() string { return "Snapshots" }
+
+func (m Model) HandlesBack() bool { return m.confirming || m.pane.Running() }
+
+// HandlesInterrupt passes ctrl+c to the running command.
+func (m Model) HandlesInterrupt() bool { return m.pane.Running() }
+
+func (m Model) ShortHelp() []string {
+	if m.pane.Running() {
+		return m.pane.ShortHelp()
+	}
+	if m.confirming {
+		return []string{"y confirm", "n cancel"}
+	}
+	if len(m.entries) == 0 {
+		return []string{}
+	}
+	return []string{"↑↓ navigate", "x delete"}
+}
@@ -4,8 +4,11 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/reisset/mypctools/tui/internal/app"
+	"github.com/reisset/mypctools/tui/internal/screen/backups"
 	"github.com/reisset/mypctools/tui/internal/screen/cleanup"
 	"github.com/reisset/mypctools/tui/internal/screen/services"
+	"github.com/reisset/mypctools/tui/internal/screen/settings"
+	"github.com/reisset/mypctools/tui/internal/screen/snapshots"
 	"github.com/reisset/mypctools/tui/internal/screen/update"
 	"github.com/reisset/mypctools/tui/internal/state"
 	"github.com/reisset/mypctools/tui/internal/theme"
@@ -28,19 +31,17 @@ type Model struct {
 }
 
 func New(shared *state.Shared) *Model {
-	return &Model{shared: shared, items: buildItems(theme.UseNerdIcons()), cursor: 0}
+	return &Model{shared: shared, items: buildItems(), cursor: 0}
 }
 
-func buildItems(nerdIcons bool) []menuItem {
-	iconDesc := "using ASCII fallback icons"
-	if nerdIcons {
-		iconDesc = "using Nerd Font icons"
-	}
+func buildItems() []menuItem {
 	return []menuItem{
-		{icon: "⟳", label: "Full System Update", desc: "runs pacman / apt upgrade", id: "update"},
-		{icon: "✕", label: "System Cleanup", desc: "orphans, caches, trash", id: "cleanup"},
+		{icon: "⟳", label: "Full System Update", desc: "system packages, then flatpak, snap and firmware", id: "update"},
+		{icon: "✕", label: "System Cleanup", desc: "orphans, package cache, journal, trash", id: "cleanup"},
 		{icon: "◎", label: "Service Manager", desc: "browse systemd services", id: "services"},
-		{icon: "▣", label: "Toggle Nerd Font Icons", desc: iconDesc, id: "icons"},
+		{icon: "↺", label: "Backups", desc: "configs displaced by installs", id: "backups"},
+		{icon: "◷", label: "Snapshots", desc: "rollback points taken before updates", id: "snapshots"},
+		{icon: "⚙", label: "Settings", desc: "icons, output capture, update source, services", id: "settings"},
 		{separator: true},
 		{icon: "←", label: "Back", id: "back"},
 	}
@@ -89,13 +90,12 @@ func (m *Model) handleSelection(id string) tea.Cmd {
 		return app.Navigate(cleanup.New(m.shared))
 	case "services":
 		return app.Navigate(services.New(m.shared))
-	case "icons":
-		theme.ToggleIconSet()
-		m.items = buildItems(theme.UseNerdIcons())
-		if theme.UseNerdIcons() {
-			return app.Toast("Nerd Font icons enabled", false)
-		}
-		return app.Toast("ASCII icons enabled", false)
+	case "backups":
+		return app.Navigate(backups.New(m.shared, ""))
+	case "snapshots":
+		return app.Navigate(snapshots.New(m.shared))
+	case "settings":
+		return app.Navigate(settings.New(m.shared))
 	case "back":
 		return app.PopScreen()
 	}
@@ -0,0 +1,133 @@
+package transcript
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+	"github.com/reisset/mypctools/tui/internal/app"
+	"github.com/reisset/mypctools/tui/internal/runner"
+	"github.com/reisset/mypctools/tui/internal/state"
+	"github.com/reisset/mypctools/tui/internal/theme"
+)
+
+type loadedMsg struct {
+	lines []string
+	err   error
+}
+
+// Model shows a saved run transcript, scrolled to the end where failures are.
+type Model struct {
+	shared   *state.Shared
+	path     string
+	lines    []string
+	err      error
+	loaded   bool
+	viewport viewport.Model
+}
+
+// New creates a viewer for the transcript at path.
+func New(shared *state.Shared, path string) Model {
+	m := Model{shared: shared, path: path}
+	m.viewport = viewport.New(m.size())
+	return m
+}
+
+func (m Model) Init() tea.Cmd {
+	if m.loaded {
+		return nil
+	}
+	path := m.path
+	return func() tea.Msg {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return loadedMsg{err: err}
+		}
+		return loadedMsg{lines: runner.Lines(data)}
+	}
+}
+
+func (m Model) Update(msg tea.Msg) (app.Screen, tea.Cmd) {
+	switch msg := msg.(type) {
+	case loadedMsg:
+		m.loaded = true
+		m.lines = msg.lines
+		m.err = msg.err
+		m.refresh()
+		m.viewport.GotoBottom()
+	case tea.WindowSizeMsg:
+		m.viewport.Width, m.viewport.Height = m.size()
+		m.refresh()
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "up", "k":
+			m.viewport.LineUp(1)
+		case "down", "j":
+			m.viewport.LineDown(1)
+		case "pgup", "b":
+			m.viewport.HalfViewUp()
+		case "pgdown", "f", " ":
+			m.viewport.HalfViewDown()
+		case "home", "g":
+			m.viewport.GotoTop()
+		case "end", "G":
+			m.viewport.GotoBottom()
+		}
+	}
+	return m, nil
+}
+
+func (m *Model) refresh() {
+	out := make([]string, len(m.lines))
+	for i, l := range m.lines {
+		out[i] = runewidth.Truncate(l, m.viewport.Width, "")
+	}
+	m.viewport.SetContent(strings.Join(out, "\n"))
+}
+
+func (m Model) size() (int, int) {
+	w := m.shared.TerminalWidth - 4
+	if w < 20 {
+		w = 76
+	}
+	h := m.shared.ContentHeight - 2
+	if h < 5 {
+		h = 5
+	}
+	return w, h
+}
+
+func (m Model) View() string {
+	width := m.shared.TerminalWidth
+	if width == 0 {
+		width = 80
+	}
+	center := func(s string) string {
+		return lipgloss.NewStyle().Width(width).Align(lipgloss.Center).Render(s)
+	}
+
+	if m.err != nil {
+		return center(theme.ErrorStyle().Render("Could not read transcript: " + m.err.Error()))
+	}
+	if !m.loaded {
+		return center(theme.MutedStyle().Render("Loading transcript..."))
+	}
+
+	status := theme.MutedStyle().Render(filepath.Base(m.path))
+	return lipgloss.JoinVertical(lipgloss.Left,
+		center(status),
+		lipgloss.NewStyle().PaddingLeft(2).Render(m.viewport.View()),
+	)
+}
+
+func (m Model) Title() string { return "Output Log" }
+
+func (m Model) HandlesBack() bool { return false }
+
+func (m Model) ShortHelp() []string {
+	return []string{"↑↓ scroll", "pgup/pgdn page", "home/end jump"}
+}
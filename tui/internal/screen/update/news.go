@@ -0,0 +1,88 @@
+package update
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/reisset/mypctools/tui/internal/app"
+	"github.com/reisset/mypctools/tui/internal/logging"
+	"github.com/reisset/mypctools/tui/internal/theme"
+)
+
+// updateNews scrolls the news and waits for the user to acknowledge it.
+func (m Model) updateNews(msg tea.KeyMsg) (app.Screen, tea.Cmd) {
+	switch msg.String() {
+	case "up", "k":
+		m.newsView.LineUp(1)
+	case "down", "j":
+		m.newsView.LineDown(1)
+	case "pgup":
+		m.newsView.HalfViewUp()
+	case "pgdown", " ":
+		m.newsView.HalfViewDown()
+	case "enter", "y":
+		titles := make([]string, len(m.news))
+		for i, it := range m.news {
+			titles[i] = it.Title
+		}
+		logging.LogAction("Acknowledged Arch news: " + strings.Join(titles, "; ")) //nolint:errcheck
+		return m.refresh()
+	}
+	return m, nil
+}
+
+// renderNews lays out every unread post, newest first.
+func (m Model) renderNews() string {
+	width := m.newsView.Width
+	title := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(theme.Current.Primary))
+	body := lipgloss.NewStyle().Width(width)
+
+	var parts []string
+	for i, it := range m.news {
+		if i > 0 {
+			parts = append(parts, "", theme.HelpDividerStyle().Render(strings.Repeat("─", min(width, 40))), "")
+		}
+		parts = append(parts,
+			title.Width(width).Render(it.Title),
+			theme.MutedStyle().Render(it.Published.Local().Format("2006-01-02")+"  "+it.Link),
+			"",
+			body.Render(it.Body),
+		)
+	}
+	return strings.Join(parts, "\n")
+}
+
+func (m Model) newsSize() (int, int) {
+	w := min(m.shared.TerminalWidth-6, 90)
+	if w < 20 {
+		w = 74
+	}
+	h := m.shared.ContentHeight - 4
+	if h < 5 {
+		h = 5
+	}
+	return w, h
+}
+
+func (m Model) viewNews(center func(string) string) string {
+	heading := fmt.Sprintf("%d Arch news posts since your last update", len(m.news))
+	if len(m.news) == 1 {
+		heading = "1 Arch news post since your last update"
+	}
+	hint := "Read before upgrading — some require manual intervention"
+	view := lipgloss.NewStyle().Width(m.newsView.Width).Render(m.newsView.View())
+	return lipgloss.JoinVertical(lipgloss.Left,
+		center(theme.WarningStyle().Render("⚠ "+heading)),
+		center(theme.MutedStyle().Render(hint)),
+		"",
+		center(view),
+	)
+}
+
+// firstLine trims s to its first line.
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
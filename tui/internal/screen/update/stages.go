@@ -0,0 +1,169 @@
+package update
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/reisset/mypctools/tui/internal/app"
+	"github.com/reisset/mypctools/tui/internal/logging"
+	"github.com/reisset/mypctools/tui/internal/pkgmgr"
+	"github.com/reisset/mypctools/tui/internal/system"
+	"github.com/reisset/mypctools/tui/internal/theme"
+)
+
+type stageStatus int
+
+const (
+	stagePending stageStatus = iota
+	stageSkipped
+	stageOK
+	stageFailed
+)
+
+// stage is one step of the update: the native packages, then each extra
+// source (flatpak, snap, firmware).
+type stage struct {
+	name       string
+	command    func() *exec.Cmd
+	status     stageStatus
+	note       string // Why it was skipped
+	err        error
+	transcript string
+}
+
+// buildStages lays out the run. The native stage's command is filled in
+// once the user has reviewed the upgrades.
+func buildStages(pkgName string, upToDate bool) []stage {
+	stages := []stage{{name: "System packages (" + pkgName + ")"}}
+	if upToDate {
+		stages[0].status = stageSkipped
+		stages[0].note = "up to date"
+	}
+	for _, e := range pkgmgr.Extras {
+		s := stage{name: e.Name, command: e.Command}
+		if !e.Available() {
+			s.status = stageSkipped
+			s.note = e.Binary + " not installed"
+		}
+		stages = append(stages, s)
+	}
+	return stages
+}
+
+// pendingExtras lists the extra stages that will run.
+func pendingExtras(stages []stage) []string {
+	var names []string
+	for _, s := range stages[1:] {
+		if s.status == stagePending {
+			names = append(names, s.name)
+		}
+	}
+	return names
+}
+
+// nextStage runs the next pending stage, or finishes when none is left.
+func (m Model) nextStage() (app.Screen, tea.Cmd) {
+	for m.current < len(m.stages) {
+		s := m.stages[m.current]
+		if s.status == stagePending {
+			m.phase = phaseUpgrade
+			return m, m.pane.Exec(s.command(), strings.ToLower(s.name)+" update")
+		}
+		m.current++
+	}
+	return m.finishStages()
+}
+
+// stageDone records the result of the running stage and moves on; a failed
+// stage doesn't stop the others, since they are independent.
+func (m Model) stageDone(err error) (app.Screen, tea.Cmd) {
+	s := &m.stages[m.current]
+	s.err = err
+	s.status = stageOK
+	if err != nil {
+		s.status = stageFailed
+	}
+	s.transcript = m.pane.TranscriptPath()
+	m.current++
+	return m.nextStage()
+}
+
+// finishStages logs and reports the combined result.
+func (m Model) finishStages() (app.Screen, tea.Cmd) {
+	m.phase = phaseDone
+	var failed []string
+	for _, s := range m.stages {
+		if s.status == stageFailed {
+			failed = append(failed, s.name)
+		}
+	}
+	// Only a run that brought the system packages current counts for news
+	if m.systemCurrent() {
+		recordUpdate()
+	}
+	if len(failed) > 0 {
+		logging.LogAction("System update failed: " + strings.Join(failed, ", ")) //nolint:errcheck
+		system.Notify("mypctools", "System update finished with errors")
+	} else {
+		logging.LogAction("System update completed") //nolint:errcheck
+		system.Notify("mypctools", "System update completed")
+	}
+	return m, nil
+}
+
+// systemCurrent reports whether the run left no native upgrade pending:
+// there were none, or all of them were installed. Excluded packages are
+// still out of date, so their news must keep showing.
+func (m Model) systemCurrent() bool {
+	if len(m.upgrades) == 0 {
+		return true
+	}
+	return m.stages[0].status == stageOK && len(m.excluded) == 0
+}
+
+// failedStages counts stages that failed.
+func (m Model) failedStages() int {
+	n := 0
+	for _, s := range m.stages {
+		if s.status == stageFailed {
+			n++
+		}
+	}
+	return n
+}
+
+// logPath is the transcript worth reopening: the first failed stage's, else
+// the last one captured.
+func (m Model) logPath() string {
+	last := m.pane.TranscriptPath()
+	for _, s := range m.stages {
+		if s.status == stageFailed && s.transcript != "" {
+			return s.transcript
+		}
+		if s.transcript != "" {
+			last = s.transcript
+		}
+	}
+	return last
+}
+
+// stageLines renders one status line per stage.
+func (m Model) stageLines() []string {
+	icons := theme.GetIcons()
+	lines := make([]string, len(m.stages))
+	for i, s := range m.stages {
+		switch s.status {
+		case stageOK:
+			lines[i] = theme.SuccessStyle().Render(icons.Check + "  " + s.name)
+		case stageFailed:
+			lines[i] = theme.ErrorStyle().Render(fmt.Sprintf("✗  %s: %v", s.name, s.err))
+		case stageSkipped:
+			lines[i] = theme.MutedStyle().Render("—  " + s.name + " · skipped, " + s.note)
+		default:
+			lines[i] = theme.MutedStyle().Render("·  " + s.name + " · not run")
+		}
+	}
+	return lines
+}
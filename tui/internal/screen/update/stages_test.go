@@ -0,0 +1,30 @@
+package update
+
+import (
+	"testing"
+
+	"github.com/reisset/mypctools/tui/internal/pkgmgr"
+)
+
+func TestSystemCurrent(t *testing.T) {
+	upgrades := []pkgmgr.Upgrade{{Name: "linux"}, {Name: "mesa"}}
+	tests := []struct {
+		name     string
+		upgrades []pkgmgr.Upgrade
+		excluded map[string]bool
+		native   stageStatus
+		want     bool
+	}{
+		{"up to date", nil, nil, stageSkipped, true},
+		{"all upgraded", upgrades, nil, stageOK, true},
+		{"some excluded", upgrades, map[string]bool{"linux": true}, stageOK, false},
+		{"all excluded", upgrades, map[string]bool{"linux": true, "mesa": true}, stageSkipped, false},
+		{"upgrade failed", upgrades, nil, stageFailed, false},
+	}
+	for _, tt := range tests {
+		m := Model{upgrades: tt.upgrades, excluded: tt.excluded, stages: []stage{{status: tt.native}}}
+		if got := m.systemCurrent(); got != tt.want {
+			t.Errorf("%s: systemCurrent() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -2,109 +2,509 @@ package update
 
 import (
 	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
 
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/reisset/mypctools/tui/internal/app"
+	"github.com/reisset/mypctools/tui/internal/archnews"
+	"github.com/reisset/mypctools/tui/internal/cmd"
+	"github.com/reisset/mypctools/tui/internal/fssnap"
 	"github.com/reisset/mypctools/tui/internal/logging"
+	"github.com/reisset/mypctools/tui/internal/pkgmgr"
+	"github.com/reisset/mypctools/tui/internal/runner"
+	"github.com/reisset/mypctools/tui/internal/screen/transcript"
 	"github.com/reisset/mypctools/tui/internal/state"
-	"github.com/reisset/mypctools/tui/internal/system"
 	"github.com/reisset/mypctools/tui/internal/theme"
+	"github.com/reisset/mypctools/tui/internal/ui"
 )
 
-// Model handles the full system update screen.
+type phase int
+
+const (
+	phaseNewsLoading phase = iota // Fetching Arch news (Arch family only)
+	phaseNews                     // Unread news waiting for acknowledgment
+	phaseRefresh                  // Syncing package lists (apt, zypper)
+	phaseListing                  // Asking the package manager what would change
+	phaseReview                   // User picks which upgrades to take
+	phaseSnapshot                 // Taking a filesystem snapshot before upgrading
+	phaseUpgrade                  // Running the native and extra stages
+	phaseDone
+)
+
+// upgradesMsg carries the pending upgrades found before anything runs.
+type upgradesMsg struct {
+	upgrades []pkgmgr.Upgrade
+	err      error
+}
+
+// newsMsg carries the Arch news posted since the last update.
+type newsMsg struct {
+	items []archnews.Item
+	err   error
+}
+
+// Model handles the full system update screen. On Arch it first shows news
+// posted since the last update, then lists pending upgrades so kernel, libc
+// and driver bumps are seen before they are applied.
 type Model struct {
-	shared *state.Shared
-	done   bool
-	err    error
+	shared   *state.Shared
+	phase    phase
+	news     []archnews.Item
+	newsErr  error // Feed could not be read; shown as a warning during review
+	newsView viewport.Model
+	upgrades []pkgmgr.Upgrade // Critical packages first
+	excluded map[string]bool
+	cursor   int
+	snapshot bool            // Take a filesystem snapshot before upgrading
+	pending  *fssnap.Pending // Snapshot being taken
+	snapped  *fssnap.Entry   // Snapshot taken before this upgrade
+	stages   []stage
+	current  int // Index of the running stage
+	err      error
+	shimmer  ui.Shimmer
+	pane     runner.Pane
 }
 
 // New creates a new update screen.
 func New(shared *state.Shared) Model {
 	return Model{
-		shared: shared,
+		shared:   shared,
+		excluded: make(map[string]bool),
+		snapshot: shared.Snap != nil,
+		shimmer:  ui.Shimmer{Text: "Checking Arch news..."},
+		pane:     runner.NewPane(shared),
 	}
 }
 
 func (m Model) Init() tea.Cmd {
-	cmd := system.UpdateCommand(m.shared.Distro.Type)
-	if cmd == nil {
+	// Also called when returning from the log viewer; never rerun.
+	if m.phase != phaseNewsLoading {
+		return nil
+	}
+	if m.shared.Pkg == nil {
 		return func() tea.Msg {
 			return app.ExecDoneMsg{Err: fmt.Errorf("unsupported distro: %s", m.shared.Distro.Type)}
 		}
 	}
+	if m.shared.Distro.Type == cmd.DistroArch {
+		return tea.Batch(m.shimmer.Tick(), loadNews)
+	}
+	return func() tea.Msg { return newsMsg{} }
+}
+
+// newsWindow bounds how far back news is shown when no previous update is known.
+const newsWindow = 30 * 24 * time.Hour
+
+// loadNews reads the Arch news feed and keeps the posts newer than the last
+// successful update (recorded by mypctools, else taken from pacman's log).
+func loadNews() tea.Msg {
+	items, err := archnews.Load()
+	if err != nil {
+		return newsMsg{err: err}
+	}
+	store, _ := state.LoadStore()
+	since := store.LastUpdate
+	if since.IsZero() {
+		since = archnews.LastPacmanUpgrade()
+	}
+	if since.IsZero() {
+		since = time.Now().Add(-newsWindow)
+	}
+	return newsMsg{items: archnews.Since(items, since)}
+}
 
-	return tea.ExecProcess(cmd, func(err error) tea.Msg {
-		return app.ExecDoneMsg{Err: err}
+// refresh syncs package lists, or goes straight to listing upgrades when
+// the package manager doesn't need it.
+func (m Model) refresh() (app.Screen, tea.Cmd) {
+	m.phase = phaseRefresh
+	if refresh := m.shared.Pkg.Refresh(); refresh != nil {
+		return m, m.pane.Exec(refresh, "package list refresh")
+	}
+	m.phase = phaseListing
+	m.shimmer = ui.Shimmer{Text: "Checking for upgrades..."}
+	return m, m.listUpgrades()
+}
+
+// listUpgrades queries pending upgrades in the background.
+func (m Model) listUpgrades() tea.Cmd {
+	pm := m.shared.Pkg
+	return tea.Batch(m.shimmer.Tick(), func() tea.Msg {
+		upgrades, err := pm.ListUpgradable()
+		return upgradesMsg{upgrades: upgrades, err: err}
 	})
 }
 
 func (m Model) Update(msg tea.Msg) (app.Screen, tea.Cmd) {
+	pane, cmd, handled := m.pane.Update(msg)
+	m.pane = pane
+	if handled {
+		return m, cmd
+	}
+
+	if m.phase == phaseNewsLoading || m.phase == phaseListing {
+		if cmd := (&m.shimmer).Update(msg); cmd != nil {
+			return m, cmd
+		}
+	}
+
 	switch msg := msg.(type) {
+	case newsMsg:
+		m.newsErr = msg.err
+		if len(msg.items) == 0 {
+			return m.refresh()
+		}
+		m.news = msg.items
+		m.phase = phaseNews
+		m.newsView = viewport.New(m.newsSize())
+		m.newsView.SetContent(m.renderNews())
+		return m, nil
+
+	case tea.WindowSizeMsg:
+		if m.phase == phaseNews {
+			m.newsView.Width, m.newsView.Height = m.newsSize()
+			m.newsView.SetContent(m.renderNews())
+		}
+		return m, nil
+
 	case app.ExecDoneMsg:
-		m.done = true
-		m.err = msg.Err
-		if msg.Err != nil {
-			logging.LogAction("System update failed")
-		} else {
-			logging.LogAction("System update completed")
-			system.Notify("mypctools", "System update completed")
+		if m.phase == phaseSnapshot {
+			return m.snapshotDone(msg.Err)
+		}
+		if m.phase == phaseUpgrade {
+			return m.stageDone(msg.Err)
+		}
+		if m.phase == phaseRefresh && msg.Err == nil {
+			m.phase = phaseListing
+			m.shimmer = ui.Shimmer{Text: "Checking for upgrades..."}
+			return m, m.listUpgrades()
+		}
+		return m.fail(msg.Err)
+
+	case upgradesMsg:
+		if msg.err != nil {
+			return m.fail(fmt.Errorf("could not list upgrades: %w", msg.err))
+		}
+		if len(msg.upgrades) == 0 {
+			m.stages = buildStages(m.shared.Pkg.Name(), true)
+			if len(pendingExtras(m.stages)) > 0 {
+				return m.nextStage()
+			}
+			logging.LogAction("System update: already up to date") //nolint:errcheck
+			recordUpdate()
 			icons := theme.GetIcons()
-			return m, app.Toast(icons.Check+" System update completed", false)
+			return m, app.Toast(icons.Check+" System is up to date", false)
 		}
+		m.upgrades = sortCritical(msg.upgrades)
+		m.stages = buildStages(m.shared.Pkg.Name(), false)
+		m.phase = phaseReview
 		return m, nil
 
 	case tea.KeyMsg:
-		if m.done {
+		switch m.phase {
+		case phaseNews:
+			return m.updateNews(msg)
+		case phaseReview:
+			return m.updateReview(msg)
+		case phaseDone:
+			if msg.String() == "l" && m.logPath() != "" {
+				return m, app.Navigate(transcript.New(m.shared, m.logPath()))
+			}
 			return m, app.PopScreen()
 		}
 	}
 	return m, nil
 }
 
+func (m Model) updateReview(msg tea.KeyMsg) (app.Screen, tea.Cmd) {
+	switch msg.String() {
+	case "down":
+		m.cursor++
+		if m.cursor >= len(m.upgrades) {
+			m.cursor = 0
+		}
+	case "up":
+		m.cursor--
+		if m.cursor < 0 {
+			m.cursor = len(m.upgrades) - 1
+		}
+	case " ":
+		name := m.upgrades[m.cursor].Name
+		if m.excluded[name] {
+			delete(m.excluded, name)
+		} else {
+			m.excluded[name] = true
+		}
+	case "a":
+		if len(m.excluded) > 0 {
+			m.excluded = make(map[string]bool)
+		} else {
+			for _, u := range m.upgrades {
+				m.excluded[u.Name] = true
+			}
+		}
+	case "s":
+		if m.shared.Snap != nil {
+			m.snapshot = !m.snapshot
+		}
+	case "enter":
+		if len(m.excluded) == len(m.upgrades) {
+			// Nothing native to do; still run flatpak and friends if present
+			if len(pendingExtras(m.stages)) == 0 {
+				return m, app.PopScreen()
+			}
+			m.stages[0].status = stageSkipped
+			m.stages[0].note = "all packages excluded"
+			return m.nextStage()
+		}
+		if m.snapshot {
+			return m.takeSnapshot()
+		}
+		return m.upgrade()
+	}
+	return m, nil
+}
+
+// takeSnapshot creates a rollback point before anything is upgraded.
+func (m Model) takeSnapshot() (app.Screen, tea.Cmd) {
+	tool := m.shared.Snap
+	c, pending, err := fssnap.Create(tool, "system update")
+	if err != nil {
+		return m.fail(fmt.Errorf("could not start %s snapshot: %w", tool.Name(), err))
+	}
+	m.phase = phaseSnapshot
+	m.pending = pending
+	return m, m.pane.Exec(c, tool.Name()+" snapshot")
+}
+
+// snapshotDone records the snapshot and moves on to the upgrade. A failed
+// snapshot stops the update: the user asked for a rollback point.
+func (m Model) snapshotDone(err error) (app.Screen, tea.Cmd) {
+	pending := m.pending
+	m.pending = nil
+	if err != nil {
+		pending.Discard()
+		return m.fail(fmt.Errorf("snapshot failed, nothing was upgraded: %w", err))
+	}
+	e, err := pending.Finish()
+	if err != nil {
+		logging.LogAction(fmt.Sprintf("Could not record %s snapshot: %v", m.shared.Snap.Name(), err)) //nolint:errcheck
+	} else {
+		m.snapped = &e
+		logging.LogAction(fmt.Sprintf("Created %s snapshot %s before system update", e.Tool, e.ID)) //nolint:errcheck
+	}
+	return m.upgrade()
+}
+
+// upgrade runs the package manager, leaving excluded packages alone.
+func (m Model) upgrade() (app.Screen, tea.Cmd) {
+	var exclude []string
+	for _, u := range m.upgrades {
+		if m.excluded[u.Name] {
+			exclude = append(exclude, u.Name)
+		}
+	}
+	if len(exclude) > 0 {
+		logging.LogAction(fmt.Sprintf("System update excluding: %v", exclude)) //nolint:errcheck
+	}
+	pm := m.shared.Pkg
+	m.stages[0].command = func() *exec.Cmd { return pm.Update(exclude...) }
+	m.current = 0
+	return m.nextStage()
+}
+
+// fail stops before any stage ran (refresh, listing or snapshot failed).
+func (m Model) fail(err error) (app.Screen, tea.Cmd) {
+	m.phase = phaseDone
+	m.err = err
+	logging.LogAction(fmt.Sprintf("System update failed: %v", err)) //nolint:errcheck
+	return m, nil
+}
+
+// recordUpdate remembers when the system was last brought up to date, so
+// the next run only shows news posted after it.
+func recordUpdate() {
+	state.UpdateStore(func(s *state.Store) { s.LastUpdate = time.Now() }) //nolint:errcheck
+}
+
+// sortCritical moves kernel, libc and driver upgrades to the top, keeping
+// the package manager's order otherwise.
+func sortCritical(upgrades []pkgmgr.Upgrade) []pkgmgr.Upgrade {
+	sort.SliceStable(upgrades, func(i, j int) bool {
+		_, ci := pkgmgr.Critical(upgrades[i].Name)
+		_, cj := pkgmgr.Critical(upgrades[j].Name)
+		return ci && !cj
+	})
+	return upgrades
+}
+
 func (m Model) View() string {
 	width := m.shared.TerminalWidth
 	if width == 0 {
 		width = 80
 	}
+	center := func(s string) string {
+		return lipgloss.NewStyle().Width(width).Align(lipgloss.Center).Render(s)
+	}
 
-	var content string
+	switch m.phase {
+	case phaseNewsLoading, phaseListing:
+		return center(m.shimmer.View())
+	case phaseNews:
+		return m.viewNews(center)
+	case phaseReview:
+		return m.viewReview(center)
+	case phaseDone:
+		return m.viewDone(center)
+	}
 
-	if m.done {
-		var statusLine string
-		if m.err != nil {
-			statusLine = theme.ErrorStyle().Render(fmt.Sprintf("Update failed: %v", m.err))
-		} else {
-			icons := theme.GetIcons()
-			statusLine = theme.SuccessStyle().Render(icons.Check + " System update completed successfully")
+	if m.pane.Running() {
+		switch m.phase {
+		case phaseRefresh:
+			return m.pane.View("Refreshing package lists")
+		case phaseSnapshot:
+			return m.pane.View("Taking " + m.shared.Snap.Name() + " snapshot")
+		case phaseUpgrade:
+			return m.pane.View("Updating " + m.stages[m.current].name)
 		}
+		return m.pane.View("Running system update")
+	}
+	return center(theme.MutedStyle().Render("Running system update..."))
+}
 
-		prompt := theme.MutedStyle().Render("Press any key to continue...")
+// viewDone reports each stage, or why the update stopped before any ran.
+func (m Model) viewDone(center func(string) string) string {
+	var statusLine string
+	switch {
+	case m.err != nil:
+		statusLine = theme.ErrorStyle().Render(fmt.Sprintf("Update failed: %v", m.err))
+	case m.failedStages() > 0:
+		statusLine = theme.WarningStyle().Render("⚠ System update finished with errors")
+	default:
+		icons := theme.GetIcons()
+		statusLine = theme.SuccessStyle().Render(icons.Check + " System update completed successfully")
+	}
 
-		content = lipgloss.JoinVertical(lipgloss.Center,
-			"",
-			statusLine,
-			"",
-			prompt,
-		)
-	} else {
-		content = theme.MutedStyle().Render("Running system update...")
+	promptText := "Press any key to continue..."
+	if m.logPath() != "" {
+		promptText = "Press l to view the output log, any other key to continue..."
 	}
 
-	return lipgloss.NewStyle().
-		Width(width).
-		Align(lipgloss.Center).
-		Render(content)
+	parts := []string{"", center(statusLine), ""}
+	if m.err == nil && len(m.stages) > 0 {
+		lines := m.stageLines()
+		w := 0
+		for _, l := range lines {
+			w = max(w, lipgloss.Width(l))
+		}
+		parts = append(parts, center(lipgloss.NewStyle().Width(w).Render(strings.Join(lines, "\n"))), "")
+	}
+	if m.snapped != nil {
+		parts = append(parts, center(theme.MutedStyle().Render(
+			fmt.Sprintf("Rollback point: %s snapshot %s", m.snapped.Tool, m.snapped.ID))), "")
+	}
+	parts = append(parts, center(theme.MutedStyle().Render(promptText)))
+	return lipgloss.JoinVertical(lipgloss.Left, parts...)
+}
+
+func (m Model) viewReview(center func(string) string) string {
+	critical := 0
+	items := make([]ui.ListItem, len(m.upgrades))
+	for i, u := range m.upgrades {
+		version := u.Available
+		if u.Current != "" {
+			version = u.Current + " → " + u.Available
+		}
+		suffix := theme.MutedStyle().Render(version)
+		if reason, ok := pkgmgr.Critical(u.Name); ok {
+			if !m.excluded[u.Name] {
+				critical++
+			}
+			suffix = theme.WarningStyle().Render("⚠ "+reason) + "  " + suffix
+		}
+		icon := "◆"
+		if m.excluded[u.Name] {
+			icon = "◇"
+		}
+		items[i] = ui.ListItem{
+			Icon:   icon,
+			Label:  u.Name,
+			Suffix: suffix,
+			Dimmed: m.excluded[u.Name],
+		}
+	}
+
+	n := len(m.upgrades) - len(m.excluded)
+	summary := fmt.Sprintf("%d of %d upgrades selected", n, len(m.upgrades))
+	if n == len(m.upgrades) {
+		summary = fmt.Sprintf("%d upgrades pending", n)
+	}
+	parts := []string{center(lipgloss.NewStyle().Foreground(lipgloss.Color(theme.Current.Primary)).Render(summary))}
+	if critical > 0 {
+		parts = append(parts, center(theme.WarningStyle().Render(
+			"⚠ kernel, libc or driver upgrades included — a reboot may be needed")))
+	}
+	if len(m.excluded) > 0 && m.shared.Distro.Type == cmd.DistroArch {
+		parts = append(parts, center(theme.WarningStyle().Render(
+			"⚠ skipping packages is a partial upgrade — Arch does not support it")))
+	}
+	if extras := pendingExtras(m.stages); len(extras) > 0 {
+		parts = append(parts, center(theme.MutedStyle().Render("Then updates: "+strings.Join(extras, ", "))))
+	}
+	if tool := m.shared.Snap; tool != nil {
+		line := "No snapshot before upgrading · s to take one"
+		if m.snapshot {
+			line = "Takes a " + tool.Name() + " snapshot first · s to skip"
+		}
+		parts = append(parts, center(theme.MutedStyle().Render(line)))
+	}
+	if m.newsErr != nil {
+		parts = append(parts, center(theme.MutedStyle().Render(
+			"Arch news not checked: "+firstLine(m.newsErr.Error()))))
+	}
+
+	height := m.shared.ContentHeight - 6
+	if height < 5 {
+		height = 5
+	}
+	list := ui.RenderList(items, m.cursor, ui.ListConfig{
+		Width:         72,
+		MaxInnerWidth: 72,
+		Height:        height,
+	})
+	parts = append(parts, "", center(list))
+	return lipgloss.JoinVertical(lipgloss.Left, parts...)
 }
 
 func (m Model) Title() string {
 	return "Full System Update"
 }
 
-func (m Model) HandlesBack() bool { return false }
+func (m Model) HandlesBack() bool { return m.pane.Running() }
+
+// HandlesInterrupt passes ctrl+c to the running command.
+func (m Model) HandlesInterrupt() bool { return m.pane.Running() }
 
 func (m Model) ShortHelp() []string {
-	if m.done {
+	if m.pane.Running() {
+		return m.pane.ShortHelp()
+	}
+	switch m.phase {
+	case phaseNews:
+		return []string{"↑↓ scroll", "enter acknowledge", "esc cancel"}
+	case phaseReview:
+		if m.shared.Snap != nil {
+			return []string{"space exclude", "a all", "s snapshot", "enter upgrade", "esc cancel"}
+		}
+		return []string{"space exclude", "a all", "enter upgrade", "esc cancel"}
+	case phaseDone:
+		if m.logPath() != "" {
+			return []string{"l view log", "any key continue"}
+		}
 		return []string{"any key continue"}
 	}
 	return []string{}
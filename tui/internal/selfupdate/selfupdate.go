@@ -20,10 +20,11 @@ const (
 	releaseBaseURL = "https://github.com/reisset/mypctools/releases/latest/download"
 )
 
-// Update pulls the latest scripts then downloads and replaces the binary.
+// Update pulls the latest scripts from remote/branch then downloads and
+// replaces the binary.
 // Scripts are updated first so that a binary-download failure leaves the repo
 // in a clean state (old binary, new scripts) rather than a partially-updated one.
-func Update(scriptsDir string) error {
+func Update(scriptsDir, remote, branch string) error {
 	// Get current executable path
 	exePath, err := os.Executable()
 	if err != nil {
@@ -36,7 +37,7 @@ func Update(scriptsDir string) error {
 
 	// Pull latest scripts first — if this fails nothing has been modified.
 	fmt.Println("Pulling latest scripts...")
-	if err := gitPull(scriptsDir); err != nil {
+	if err := gitPull(scriptsDir, remote, branch); err != nil {
 		return fmt.Errorf("failed to pull scripts: %w", err)
 	}
 	fmt.Println("Scripts updated.")
@@ -144,9 +145,9 @@ func fetchExpectedChecksum(checksumsURL, filename string) (string, error) {
 	return "", fmt.Errorf("checksum not found for %s", filename)
 }
 
-// gitPull runs git pull in the specified directory.
-func gitPull(dir string) error {
-	cmd := exec.Command("git", "pull", "--ff-only")
+// gitPull fast-forwards the specified directory to remote/branch.
+func gitPull(dir, remote, branch string) error {
+	cmd := exec.Command("git", "pull", "--ff-only", remote, branch)
 	cmd.Dir = dir
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
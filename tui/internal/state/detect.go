@@ -8,21 +8,22 @@ import (
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/reisset/mypctools/tui/internal/config"
 )
 
-// UpdateCountMsg carries the number of commits behind origin/main.
+// UpdateCountMsg carries the number of commits behind the update upstream.
 type UpdateCountMsg struct {
 	Count int
 }
 
 // CheckForUpdates runs git fetch + rev-list in the background.
-func CheckForUpdates(rootDir string) tea.Cmd {
+func CheckForUpdates(rootDir string, u config.Update) tea.Cmd {
 	return func() tea.Msg {
 		// Separate contexts — fetch gets 5s, rev-list gets 2s.
 		fetchCtx, fetchCancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer fetchCancel()
 
-		cmd := exec.CommandContext(fetchCtx, "git", "-C", rootDir, "fetch", "origin", "main")
+		cmd := exec.CommandContext(fetchCtx, "git", "-C", rootDir, "fetch", u.Remote, u.Branch)
 		if err := cmd.Run(); err != nil {
 			return UpdateCountMsg{Count: 0}
 		}
@@ -30,7 +31,7 @@ func CheckForUpdates(rootDir string) tea.Cmd {
 		revCtx, revCancel := context.WithTimeout(context.Background(), 2*time.Second)
 		defer revCancel()
 
-		out, err := exec.CommandContext(revCtx, "git", "-C", rootDir, "rev-list", "HEAD..origin/main", "--count").Output()
+		out, err := exec.CommandContext(revCtx, "git", "-C", rootDir, "rev-list", "HEAD.."+u.Upstream(), "--count").Output()
 		if err != nil {
 			return UpdateCountMsg{Count: 0}
 		}
@@ -1,12 +1,20 @@
 package state
 
-import "github.com/reisset/mypctools/tui/internal/cmd"
+import (
+	"github.com/reisset/mypctools/tui/internal/cmd"
+	"github.com/reisset/mypctools/tui/internal/config"
+	"github.com/reisset/mypctools/tui/internal/fssnap"
+	"github.com/reisset/mypctools/tui/internal/pkgmgr"
+)
 
 // Shared holds global state accessible by all screens.
 type Shared struct {
 	Distro         cmd.DistroInfo
-	RootDir        string // Absolute path to mypctools repo root
-	UpdateCount    int    // Commits behind origin/main (0 = up to date)
+	Config         config.File           // config.toml, edited by the Settings screen
+	Pkg            pkgmgr.PackageManager // nil on unsupported distros
+	Snap           fssnap.Tool           // nil when no snapshot tool is available
+	RootDir        string                // Absolute path to mypctools repo root
+	UpdateCount    int                   // Commits behind the update upstream (0 = up to date)
 	TerminalWidth  int
 	TerminalHeight int
 	ContentHeight  int // TerminalHeight minus header/footer chrome (~8 lines)
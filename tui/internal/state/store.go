@@ -0,0 +1,81 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/reisset/mypctools/tui/internal/logging"
+)
+
+// storeName is the persisted state file under logging.DataDir.
+const storeName = "state.json"
+
+// Store is state mypctools keeps between runs.
+type Store struct {
+	LastUpdate time.Time `json:"last_update"` // Last successful full system update
+}
+
+var storeMu sync.Mutex
+
+func storePath() (string, error) {
+	dir := logging.DataDir()
+	if dir == "" {
+		return "", fmt.Errorf("failed to determine data directory")
+	}
+	return filepath.Join(dir, storeName), nil
+}
+
+// LoadStore reads the persisted state. A missing file yields an empty Store.
+func LoadStore() (Store, error) {
+	storeMu.Lock()
+	defer storeMu.Unlock()
+	return loadStore()
+}
+
+func loadStore() (Store, error) {
+	var s Store
+	path, err := storePath()
+	if err != nil {
+		return s, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return s, err
+	}
+	if err := json.Unmarshal(data, &s); err != nil {
+		return Store{}, fmt.Errorf("invalid %s: %w", storeName, err)
+	}
+	return s, nil
+}
+
+// UpdateStore applies fn to the persisted state and atomically saves it.
+func UpdateStore(fn func(*Store)) error {
+	storeMu.Lock()
+	defer storeMu.Unlock()
+
+	s, err := loadStore()
+	if err != nil {
+		s = Store{} // Start over rather than never saving again
+	}
+	fn(&s)
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	path, err := storePath()
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -2,58 +2,121 @@ package system
 
 import (
 	"errors"
+	"fmt"
+	"io/fs"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 
-	"github.com/reisset/mypctools/tui/internal/cmd"
+	"github.com/reisset/mypctools/tui/internal/config"
+	"github.com/reisset/mypctools/tui/internal/pkgmgr"
 )
 
-// CleanupCommand returns the package cleanup command for the given distro type.
-func CleanupCommand(distroType cmd.DistroType) *exec.Cmd {
-	switch distroType {
-	case cmd.DistroDebian:
-		return exec.Command("bash", "-c", "sudo apt autoremove -y && sudo apt autoclean && sudo apt clean")
-	case cmd.DistroArch:
-		// Remove orphans if any exist, then clear cache
-		script := `
-orphans=$(pacman -Qtdq 2>/dev/null)
-if [ -n "$orphans" ]; then
-    echo "$orphans" | sudo pacman -Rns --noconfirm -
-fi
-if command -v paccache >/dev/null 2>&1; then
-    sudo paccache -rk2
-else
-    sudo pacman -Sc --noconfirm
-fi
-`
-		return exec.Command("bash", "-c", script)
-	default:
-		return nil
-	}
+// Target is one category System Cleanup can reclaim space from. Built-in
+// targets live in cleanup_targets.go; users add path targets in config.toml.
+type Target struct {
+	ID          string
+	Name        string
+	Description string
+	// Measure reports the space the target currently takes up, in bytes.
+	Measure func() (int64, error)
+	// Plan lists what cleaning would remove, without removing anything.
+	// Optional: DryRun falls back to showing Command.
+	Plan func() ([]string, error)
+	// Command returns the command that cleans the target when it needs root
+	// or an external tool (run where sudo can prompt). Nil when there is
+	// nothing to run.
+	Command func() *exec.Cmd
+	// Clear cleans the target in-process; used when Command is nil.
+	Clear func() error
 }
 
-// ClearUserCaches removes user cache files (thumbnails, trash).
-// Returns an error if any removal fails (other than path not existing).
-func ClearUserCaches() error {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return err
+// DryRun describes what cleaning t would do.
+func (t Target) DryRun() ([]string, error) {
+	if t.Plan != nil {
+		return t.Plan()
+	}
+	if t.Command != nil {
+		c := t.Command()
+		if c == nil {
+			return nil, nil
+		}
+		return []string{"would run: " + strings.Join(c.Args, " ")}, nil
 	}
+	return nil, nil
+}
 
-	var errs []error
+// Targets returns the cleanup categories available on this system: the
+// built-ins that apply, then the user's own from config.toml. pm may be nil
+// on unsupported distros, which drops the package targets.
+func Targets(pm pkgmgr.PackageManager, user []config.CleanupTarget) []Target {
+	var targets []Target
+	if pm != nil {
+		targets = append(targets, orphansTarget(pm), pkgCacheTarget(pm))
+	}
+	targets = append(targets, builtinTargets()...)
+	for i, u := range user {
+		age, _ := config.ParseAge(u.OlderThan) // Validated by config.Load
+		desc := strings.Join(u.Paths, ", ")
+		if u.OlderThan != "" {
+			desc += " · older than " + u.OlderThan
+		}
+		targets = append(targets, pathTarget(fmt.Sprintf("user-%d", i), u.Name, desc, u.Paths, age))
+	}
+	return targets
+}
 
-	// Clear thumbnails
-	thumbnails := filepath.Join(home, ".cache", "thumbnails")
-	if err := os.RemoveAll(thumbnails); err != nil {
-		errs = append(errs, err)
+// DirSize returns the total size of the files under paths. Missing paths
+// count as empty and unreadable subdirectories are skipped, so root-owned
+// caches can be measured as far as permissions allow.
+func DirSize(paths ...string) (int64, error) {
+	var total int64
+	for _, root := range paths {
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				if path == root && errors.Is(err, fs.ErrNotExist) {
+					return fs.SkipAll
+				}
+				if errors.Is(err, fs.ErrPermission) {
+					return nil
+				}
+				return err
+			}
+			if d.Type().IsRegular() {
+				if info, err := d.Info(); err == nil {
+					total += info.Size()
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return 0, err
+		}
 	}
+	return total, nil
+}
 
-	// Clear trash
-	trash := filepath.Join(home, ".local", "share", "Trash")
-	if err := os.RemoveAll(trash); err != nil {
-		errs = append(errs, err)
+// FormatSize renders a byte count for people ("4.2 GB").
+func FormatSize(n int64) string {
+	const unit = 1000
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
 	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "kMGTPE"[exp])
+}
+
+func exists(path string) bool {
+	_, err := os.Lstat(path)
+	return err == nil
+}
 
-	return errors.Join(errs...)
+func onPath(name string) bool {
+	_, err := exec.LookPath(name)
+	return err == nil
 }
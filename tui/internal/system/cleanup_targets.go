@@ -0,0 +1,336 @@
+package system
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/reisset/mypctools/tui/internal/backup"
+	"github.com/reisset/mypctools/tui/internal/config"
+	"github.com/reisset/mypctools/tui/internal/pkgmgr"
+)
+
+// journalKeep is how much journal history the journal target keeps.
+const journalKeep = 14 * 24 * time.Hour
+
+// journalDirs hold the persistent and the volatile journals.
+var journalDirs = []string{"/var/log/journal", "/run/log/journal"}
+
+// coredumpDir is where systemd-coredump stores crash dumps.
+const coredumpDir = "/var/lib/systemd/coredump"
+
+// backupMaxAge is how old a safe_symlink backup must be to be cleaned.
+const backupMaxAge = 30 * 24 * time.Hour
+
+func orphansTarget(pm pkgmgr.PackageManager) Target {
+	return Target{
+		ID:          "orphans",
+		Name:        "Orphaned packages",
+		Description: "dependencies nothing needs anymore",
+		Measure: func() (int64, error) {
+			pkgs, err := pm.Orphans()
+			if err != nil {
+				return 0, err
+			}
+			return pm.InstalledSize(pkgs...)
+		},
+		Plan: func() ([]string, error) {
+			pkgs, err := pm.Orphans()
+			if err != nil {
+				return nil, err
+			}
+			lines := make([]string, len(pkgs))
+			for i, p := range pkgs {
+				lines[i] = "remove package " + p
+			}
+			return lines, nil
+		},
+		// Asks again rather than reusing Measure's list, which may be out of
+		// date by the time the cleanup runs. A failed query shows in Measure.
+		Command: func() *exec.Cmd {
+			pkgs, err := pm.Orphans()
+			if err != nil || len(pkgs) == 0 {
+				return nil
+			}
+			return pm.Remove(pkgs...)
+		},
+	}
+}
+
+func pkgCacheTarget(pm pkgmgr.PackageManager) Target {
+	return Target{
+		ID:          "pkgcache",
+		Name:        "Package cache",
+		Description: "downloaded packages (" + pm.Name() + ")",
+		Measure:     func() (int64, error) { return DirSize(pm.CacheDirs()...) },
+		Command:     pm.Clean,
+	}
+}
+
+// builtinTargets returns the non-package targets that apply here.
+func builtinTargets() []Target {
+	var targets []Target
+	if onPath("journalctl") {
+		targets = append(targets, Target{
+			ID:          "journal",
+			Name:        "Systemd journal",
+			Description: "logs older than 2 weeks",
+			Measure: func() (int64, error) {
+				return archivedJournalSize(journalDirs, time.Now().Add(-journalKeep))
+			},
+			Command: func() *exec.Cmd {
+				days := int(journalKeep.Hours() / 24)
+				return exec.Command("sudo", "journalctl", fmt.Sprintf("--vacuum-time=%dd", days))
+			},
+		})
+	}
+	if exists(coredumpDir) {
+		t := pathTarget("coredumps", "Core dumps", "crash dumps from systemd-coredump", []string{coredumpDir + "/*"}, 0)
+		t.Clear = nil
+		t.Command = func() *exec.Cmd {
+			return exec.Command("sudo", "find", coredumpDir, "-mindepth", "1", "-delete")
+		}
+		targets = append(targets, t)
+	}
+	if onPath("docker") {
+		targets = append(targets, dockerTarget())
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return targets
+	}
+	targets = append(targets,
+		pathTarget("thumbnails", "Thumbnails", "image previews, rebuilt on demand", []string{filepath.Join(home, ".cache", "thumbnails")}, 0),
+		pathTarget("trash", "Trash", "files deleted from the desktop", []string{filepath.Join(home, ".local", "share", "Trash")}, 0),
+	)
+
+	// Developer caches, only offered when present
+	devCaches := []struct{ id, name, desc, path string }{
+		{"pip", "pip cache", "downloaded Python wheels", filepath.Join(home, ".cache", "pip")},
+		{"npm", "npm cache", "downloaded Node packages", filepath.Join(home, ".npm", "_cacache")},
+		{"cargo", "Cargo registry", "downloaded crate sources", filepath.Join(home, ".cargo", "registry")},
+		{"go", "Go build cache", "compiled Go packages", goCacheDir(home)},
+	}
+	for _, c := range devCaches {
+		if exists(c.path) {
+			targets = append(targets, pathTarget(c.id, c.name, c.desc, []string{c.path}, 0))
+		}
+	}
+	return append(targets, backupsTarget())
+}
+
+// goCacheDir asks go for GOCACHE, falling back to its default location.
+func goCacheDir(home string) string {
+	if out, err := exec.Command("go", "env", "GOCACHE").Output(); err == nil {
+		if dir := strings.TrimSpace(string(out)); dir != "" && dir != "off" {
+			return dir
+		}
+	}
+	return filepath.Join(home, ".cache", "go-build")
+}
+
+// pathMatch is one file or directory a path target would delete.
+type pathMatch struct {
+	path string
+	size int64
+}
+
+// pathTarget deletes whatever globs match, skipping matches modified within
+// olderThan (0 = any age).
+func pathTarget(id, name, desc string, globs []string, olderThan time.Duration) Target {
+	matches := func() ([]pathMatch, error) {
+		var out []pathMatch
+		seen := make(map[string]bool)
+		for _, g := range globs {
+			paths, err := filepath.Glob(config.ExpandHome(g))
+			if err != nil {
+				return nil, err
+			}
+			for _, p := range paths {
+				info, err := os.Lstat(p)
+				if err != nil || seen[p] {
+					continue
+				}
+				if olderThan > 0 && time.Since(info.ModTime()) < olderThan {
+					continue
+				}
+				seen[p] = true
+				size, err := DirSize(p)
+				if err != nil {
+					return nil, err
+				}
+				out = append(out, pathMatch{path: p, size: size})
+			}
+		}
+		return out, nil
+	}
+	return Target{
+		ID:          id,
+		Name:        name,
+		Description: desc,
+		Measure: func() (int64, error) {
+			ms, err := matches()
+			var total int64
+			for _, m := range ms {
+				total += m.size
+			}
+			return total, err
+		},
+		Plan: func() ([]string, error) {
+			ms, err := matches()
+			lines := make([]string, len(ms))
+			for i, m := range ms {
+				lines[i] = fmt.Sprintf("%9s  %s", FormatSize(m.size), m.path)
+			}
+			return lines, err
+		},
+		Clear: func() error {
+			ms, err := matches()
+			if err != nil {
+				return err
+			}
+			for _, m := range ms {
+				if err := os.RemoveAll(m.path); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// backupsTarget prunes old config backups made by safe_symlink, through the
+// backup ledger so the Backups screen stays in sync.
+func backupsTarget() Target {
+	old := func() ([]backup.Entry, error) {
+		all, err := backup.List()
+		var out []backup.Entry
+		for _, e := range all {
+			if !e.Time.IsZero() && time.Since(e.Time) > backupMaxAge {
+				out = append(out, e)
+			}
+		}
+		return out, err
+	}
+	return Target{
+		ID:          "backups",
+		Name:        "Old config backups",
+		Description: "*.backup.* files older than 30 days",
+		Measure: func() (int64, error) {
+			entries, err := old()
+			var total int64
+			for _, e := range entries {
+				n, _ := DirSize(e.Backup)
+				total += n
+			}
+			return total, err
+		},
+		Plan: func() ([]string, error) {
+			entries, err := old()
+			lines := make([]string, len(entries))
+			for i, e := range entries {
+				lines[i] = fmt.Sprintf("%s  %s", e.Time.Format("2006-01-02"), e.Backup)
+			}
+			return lines, err
+		},
+		Clear: func() error {
+			entries, err := old()
+			if err != nil {
+				return err
+			}
+			for _, e := range entries {
+				if err := backup.Prune(e); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// dockerTarget removes dangling images (untagged layers left by rebuilds).
+func dockerTarget() Target {
+	list := func(format string) ([]string, error) {
+		out, err := exec.Command("docker", "image", "ls", "--filter", "dangling=true", "--format", format).Output()
+		if err != nil {
+			return nil, fmt.Errorf("docker: %w", err)
+		}
+		var lines []string
+		for _, l := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+			if l != "" {
+				lines = append(lines, l)
+			}
+		}
+		return lines, nil
+	}
+	return Target{
+		ID:          "docker",
+		Name:        "Docker dangling images",
+		Description: "untagged image layers",
+		Measure: func() (int64, error) {
+			sizes, err := list("{{.Size}}")
+			if err != nil {
+				return 0, err
+			}
+			var total int64
+			for _, s := range sizes {
+				n, err := parseDockerSize(s)
+				if err != nil {
+					return 0, err
+				}
+				total += n
+			}
+			return total, nil
+		},
+		Plan: func() ([]string, error) {
+			return list("{{.ID}}  {{.Size}}  created {{.CreatedSince}}")
+		},
+		Command: func() *exec.Cmd {
+			return exec.Command("docker", "image", "prune", "-f")
+		},
+	}
+}
+
+// dockerSize matches docker's human sizes ("1.2GB", "512kB", "0B").
+var dockerSize = regexp.MustCompile(`^([\d.]+)\s*([kKMGT]?)B$`)
+
+func parseDockerSize(s string) (int64, error) {
+	m := dockerSize.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return 0, fmt.Errorf("unexpected docker size %q", s)
+	}
+	n, err := strconv.ParseFloat(m[1], 64)
+	if err != nil {
+		return 0, err
+	}
+	mult := map[string]float64{"": 1, "k": 1e3, "K": 1e3, "M": 1e6, "G": 1e9, "T": 1e12}[m[2]]
+	return int64(n * mult), nil
+}
+
+// archivedJournalSize sums the archived journal files under dirs last
+// written before cutoff: what `journalctl --vacuum-time` deletes. Active
+// files ("system.journal") are never vacuumed; archived ones carry an @ in
+// their name, or end in ~ when journald set them aside as corrupt.
+func archivedJournalSize(dirs []string, cutoff time.Time) (int64, error) {
+	var total int64
+	for _, dir := range dirs {
+		for _, pattern := range []string{"*@*.journal", "*.journal~"} {
+			paths, err := filepath.Glob(filepath.Join(dir, "*", pattern))
+			if err != nil {
+				return 0, err
+			}
+			for _, p := range paths {
+				if info, err := os.Stat(p); err == nil && info.ModTime().Before(cutoff) {
+					total += info.Size()
+				}
+			}
+		}
+	}
+	return total, nil
+}
@@ -0,0 +1,89 @@
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/reisset/mypctools/tui/internal/pkgmgr"
+)
+
+// orphanRunner answers pacman's orphan and size queries from a list that
+// can change between calls.
+type orphanRunner struct{ orphans *[]string }
+
+func (r orphanRunner) Output(name string, args ...string) ([]byte, error) {
+	var out string
+	for _, p := range *r.orphans {
+		if slices.Contains(args, "-Qtdq") {
+			out += p + "\n"
+		} else {
+			out += "Installed Size  : 1.00 KiB\n"
+		}
+	}
+	return []byte(out), nil
+}
+
+func TestOrphansCommandAsksAgain(t *testing.T) {
+	orphans := []string{"gtk-doc", "python-sphinx"}
+	target := orphansTarget(pkgmgr.NewPacman(orphanRunner{&orphans}))
+	if n, err := target.Measure(); err != nil || n != 2048 {
+		t.Fatalf("Measure = %d, %v", n, err)
+	}
+
+	// Something else removed one before the cleanup ran.
+	orphans = orphans[1:]
+	cmd := target.Command()
+	if cmd == nil {
+		t.Fatal("no command for the remaining orphan")
+	}
+	if want := []string{"sudo", "pacman", "-Rns", "--noconfirm", "python-sphinx"}; !slices.Equal(cmd.Args, want) {
+		t.Errorf("command %q, want %q", cmd.Args, want)
+	}
+
+	orphans = nil
+	if cmd := target.Command(); cmd != nil {
+		t.Errorf("command %q with no orphans left", cmd.Args)
+	}
+}
+
+func TestArchivedJournalSize(t *testing.T) {
+	dir := t.TempDir()
+	machine := filepath.Join(dir, "0123456789abcdef")
+	if err := os.Mkdir(machine, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	old := now.Add(-30 * 24 * time.Hour)
+	files := []struct {
+		name string
+		size int
+		mod  time.Time
+	}{
+		{"system.journal", 1000, old},                          // Active, never vacuumed
+		{"system@0005f3-0000000000000001.journal", 200, old},   // Archived, old enough
+		{"user-1000@0005f3-0000000000000002.journal", 30, old}, // Archived, old enough
+		{"system@0005f3-0000000000000003.journal", 4000, now},  // Archived, too recent
+		{"system@0005f3-0000000000000004.journal~", 5, old},    // Set aside as corrupt
+		{"notes.txt", 60000, old},
+	}
+	for _, f := range files {
+		path := filepath.Join(machine, f.name)
+		if err := os.WriteFile(path, make([]byte, f.size), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, f.mod, f.mod); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := archivedJournalSize([]string{dir, filepath.Join(dir, "missing")}, now.Add(-journalKeep))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != 235 {
+		t.Errorf("got %d bytes, want 235", got)
+	}
+}
@@ -1,108 +1,166 @@
 package system
 
 import (
+	"math"
+	"os"
 	"os/exec"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 )
 
-// KnownServices is the list of common services to display.
-var KnownServices = []string{
-	"docker",
-	"ssh",
-	"sshd",
-	"bluetooth",
-	"cups",
-	"NetworkManager",
-	"avahi-daemon",
-	"cron",
-	"crond",
-	"ufw",
-	"firewalld",
+// Scope selects which systemd instance units belong to.
+type Scope int
+
+const (
+	ScopeSystem Scope = iota // System units, controlled through sudo
+	ScopeUser                // The user's own units (systemctl --user), no sudo
+)
+
+func (s Scope) String() string {
+	if s == ScopeUser {
+		return "user"
+	}
+	return "system"
+}
+
+// systemctl returns a systemctl command for the scope.
+func (s Scope) systemctl(args ...string) *exec.Cmd {
+	if s == ScopeUser {
+		args = append([]string{"--user"}, args...)
+	}
+	return exec.Command("systemctl", args...)
 }
 
 // ServiceStatus holds the status of a systemd service.
 type ServiceStatus struct {
-	Name    string
-	Active  string // "active", "inactive", "failed", "unknown"
-	Enabled string // "enabled", "disabled", "static", "unknown"
-	PID     string // main PID (empty if not running)
+	Name        string
+	Exists      bool   // A unit file or loaded unit by this name exists
+	Active      string // "active", "inactive", "failed", "unknown"
+	Enabled     string // "enabled", "disabled", "static", "unknown"
+	PID         string // main PID (empty if not running)
+	Description string
+	SubState    string        // e.g. "running", "exited", "dead"
+	Since       time.Time     // When the unit last became active (zero if never)
+	Memory      int64         // Bytes in use, -1 when not tracked
+	CPU         time.Duration // CPU time consumed, -1 when not tracked
+	Tasks       int64         // Tasks (threads) running, -1 when not tracked
+	Restarts    int           // Automatic restarts since the unit was started
+	Path        string        // Unit file (FragmentPath)
+	Result      string        // Outcome of the last run ("success", "exit-code", ...)
 }
 
-// GetServiceStatus returns the status of a single service.
-func GetServiceStatus(name string) ServiceStatus {
-	status := ServiceStatus{Name: name, Active: "unknown", Enabled: "unknown"}
+// showProperties are the properties GetServiceStatus asks systemctl for.
+var showProperties = []string{
+	"LoadState", "ActiveState", "SubState", "UnitFileState", "MainPID",
+	"Description", "ActiveEnterTimestamp", "MemoryCurrent", "CPUUsageNSec",
+	"TasksCurrent", "NRestarts", "FragmentPath", "Result",
+}
 
-	// Check if service exists (systemd >= 245 exits 0 even for missing units, so check stdout)
-	if !ServiceExists(name) {
-		return status
+// GetServiceStatus returns the status of a single service, from one
+// `systemctl show` call.
+func GetServiceStatus(scope Scope, name string) ServiceStatus {
+	cmd := scope.systemctl("show", name, "--property="+strings.Join(showProperties, ","))
+	cmd.Env = append(os.Environ(), "LC_ALL=C") // Timestamps in a parseable form
+	out, err := cmd.Output()
+	if err != nil {
+		return ServiceStatus{Name: name, Active: "unknown", Enabled: "unknown", Memory: -1, CPU: -1, Tasks: -1}
 	}
+	return parseShow(name, out)
+}
 
-	// Get active status
-	if out, err := exec.Command("systemctl", "is-active", name).Output(); err == nil {
-		status.Active = strings.TrimSpace(string(out))
-	} else {
-		// is-active returns exit code 3 for inactive, still has output
-		if exitErr, ok := err.(*exec.ExitError); ok {
-			if exitErr.ExitCode() == 3 {
-				status.Active = "inactive"
-			}
+// parseShow reads `systemctl show` Key=Value output. systemd exits 0 for
+// units that don't exist, reporting LoadState=not-found.
+func parseShow(name string, out []byte) ServiceStatus {
+	props := make(map[string]string, len(showProperties))
+	for _, line := range strings.Split(string(out), "\n") {
+		if k, v, ok := strings.Cut(line, "="); ok {
+			props[k] = v
 		}
 	}
 
-	// Get enabled status
-	if out, err := exec.Command("systemctl", "is-enabled", name).Output(); err == nil {
-		status.Enabled = strings.TrimSpace(string(out))
-	} else {
-		// is-enabled returns exit code 1 for disabled
-		if exitErr, ok := err.(*exec.ExitError); ok {
-			if exitErr.ExitCode() == 1 {
-				status.Enabled = "disabled"
-			}
-		}
+	status := ServiceStatus{Name: name, Active: "unknown", Enabled: "unknown", Memory: -1, CPU: -1, Tasks: -1}
+	if props["LoadState"] == "" || props["LoadState"] == "not-found" {
+		return status
 	}
-
-	// Get main PID
-	if out, err := exec.Command("systemctl", "show", name, "--property=MainPID", "--value").Output(); err == nil {
-		pid := strings.TrimSpace(string(out))
-		if pid != "" && pid != "0" {
-			status.PID = pid
-		}
+	status.Exists = true
+	status.Description = props["Description"]
+	status.SubState = props["SubState"]
+	status.Path = props["FragmentPath"]
+	status.Result = props["Result"]
+	if v := props["ActiveState"]; v != "" {
+		status.Active = v
+	}
+	if v := props["UnitFileState"]; v != "" {
+		status.Enabled = v
+	}
+	if pid := props["MainPID"]; pid != "" && pid != "0" {
+		status.PID = pid
+	}
+	if t, err := time.ParseInLocation("Mon 2006-01-02 15:04:05 MST", props["ActiveEnterTimestamp"], time.Local); err == nil {
+		status.Since = t
+	}
+	status.Memory = counter(props["MemoryCurrent"])
+	if ns := counter(props["CPUUsageNSec"]); ns >= 0 {
+		status.CPU = time.Duration(ns)
+	}
+	status.Tasks = counter(props["TasksCurrent"])
+	if n, err := strconv.Atoi(props["NRestarts"]); err == nil {
+		status.Restarts = n
 	}
-
 	return status
 }
 
-// ServiceExists checks if a service unit file exists.
-// We check stdout because systemd >= 245 exits 0 even when the unit is not found.
-func ServiceExists(name string) bool {
-	out, err := exec.Command("systemctl", "list-unit-files", name+".service", "--no-legend").Output()
+// counter parses a resource counter, returning -1 when systemd doesn't track
+// it ("[not set]", or the all-ones "infinity" value).
+func counter(v string) int64 {
+	n, err := strconv.ParseUint(v, 10, 64)
 	if err != nil {
-		return false
+		return -1
 	}
-	return strings.Contains(string(out), name+".service")
+	return counterValue(n)
 }
 
-// GetKnownServices returns the status of all known services that exist on the system.
-func GetKnownServices() []ServiceStatus {
+// counterValue is counter for a value already read as a number.
+func counterValue(n uint64) int64 {
+	if n == math.MaxUint64 || n > math.MaxInt64 {
+		return -1
+	}
+	return int64(n)
+}
+
+// GetKnownServices returns the status of the pinned services followed by the
+// default ones (pinned and known under [services] or [services.user] in
+// config.toml), skipping duplicates and services that don't exist.
+func GetKnownServices(mgr Manager, known, pinned []string) []ServiceStatus {
 	var services []ServiceStatus
-	for _, name := range KnownServices {
-		if ServiceExists(name) {
-			services = append(services, GetServiceStatus(name))
+	seen := make(map[string]bool, len(known)+len(pinned))
+	for _, name := range append(append([]string{}, pinned...), known...) {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		if status := mgr.Status(name); status.Exists {
+			services = append(services, status)
 		}
 	}
 	return services
 }
 
 // ServiceActionCmd returns an exec.Cmd for the given service action.
-// Actions: start, stop, restart, enable, disable
-func ServiceActionCmd(name, action string) *exec.Cmd {
+// Actions: start, stop, restart, enable, disable. System units go through
+// sudo; user units belong to the user and need none.
+func ServiceActionCmd(scope Scope, name, action string) *exec.Cmd {
+	if scope == ScopeUser {
+		return scope.systemctl(action, name)
+	}
 	return exec.Command("sudo", "systemctl", action, name)
 }
 
-// ListAllServices returns all service names on the system.
-func ListAllServices() ([]string, error) {
-	out, err := exec.Command("systemctl", "list-unit-files", "--type=service", "--no-pager", "--no-legend").Output()
+// ListAllServices returns all service names in the scope.
+func ListAllServices(scope Scope) ([]string, error) {
+	out, err := scope.systemctl("list-unit-files", "--type=service", "--no-pager", "--no-legend").Output()
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,356 @@
+package system
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+const (
+	systemdDest      = "org.freedesktop.systemd1"
+	systemdPath      = dbus.ObjectPath("/org/freedesktop/systemd1")
+	systemdManager   = "org.freedesktop.systemd1.Manager"
+	systemdUnit      = "org.freedesktop.systemd1.Unit"
+	systemdService   = "org.freedesktop.systemd1.Service"
+	unitPathPrefix   = "/org/freedesktop/systemd1/unit/"
+	propertiesIface  = "org.freedesktop.DBus.Properties"
+	propertiesChange = propertiesIface + ".PropertiesChanged"
+	jobRemoved       = systemdManager + ".JobRemoved"
+)
+
+// jobTimeout bounds how long Action waits for a start, stop or restart job.
+const jobTimeout = 2 * time.Minute
+
+// dbusManager talks to systemd's org.freedesktop.systemd1 API: the system
+// bus for system units, the session bus for user units. Privileged actions
+// are authorized by polkit.
+type dbusManager struct {
+	scope Scope
+	conn  *dbus.Conn
+}
+
+func newDBusManager(scope Scope) (*dbusManager, error) {
+	connect := dbus.SystemBus
+	if scope == ScopeUser {
+		connect = dbus.SessionBus
+	}
+	conn, err := connect() // Shared connection, never closed
+	if err != nil {
+		return nil, err
+	}
+	m := &dbusManager{scope: scope, conn: conn}
+	// Make sure systemd is on the bus before committing to it.
+	var version dbus.Variant
+	if err := m.manager().Call(propertiesIface+".Get", 0, systemdManager, "Version").Store(&version); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (m *dbusManager) manager() dbus.BusObject { return m.conn.Object(systemdDest, systemdPath) }
+
+// unitName turns a service name from the UI into a unit name.
+func unitName(name string) string {
+	if strings.HasSuffix(name, ".service") {
+		return name
+	}
+	return name + ".service"
+}
+
+func (m *dbusManager) Status(name string) ServiceStatus {
+	status := ServiceStatus{Name: name, Active: "unknown", Enabled: "unknown", Memory: -1, CPU: -1, Tasks: -1}
+	var unitPath dbus.ObjectPath
+	if err := m.manager().Call(systemdManager+".LoadUnit", 0, unitName(name)).Store(&unitPath); err != nil {
+		return status
+	}
+	obj := m.conn.Object(systemdDest, unitPath)
+	var unit, service map[string]dbus.Variant
+	if err := obj.Call(propertiesIface+".GetAll", 0, systemdUnit).Store(&unit); err != nil {
+		return status
+	}
+	if s := str(unit, "LoadState"); s == "" || s == "not-found" {
+		return status
+	}
+	obj.Call(propertiesIface+".GetAll", 0, systemdService).Store(&service) //nolint:errcheck // Left empty if it fails
+
+	status.Exists = true
+	status.Description = str(unit, "Description")
+	status.SubState = str(unit, "SubState")
+	status.Path = str(unit, "FragmentPath")
+	status.Result = str(service, "Result")
+	if v := str(unit, "ActiveState"); v != "" {
+		status.Active = v
+	}
+	if v := str(unit, "UnitFileState"); v != "" {
+		status.Enabled = v
+	}
+	if pid, ok := service["MainPID"].Value().(uint32); ok && pid != 0 {
+		status.PID = strconv.FormatUint(uint64(pid), 10)
+	}
+	if us, ok := unit["ActiveEnterTimestamp"].Value().(uint64); ok && us != 0 {
+		status.Since = time.UnixMicro(int64(us))
+	}
+	if n, ok := service["MemoryCurrent"].Value().(uint64); ok {
+		status.Memory = counterValue(n)
+	}
+	if n, ok := service["CPUUsageNSec"].Value().(uint64); ok {
+		if ns := counterValue(n); ns >= 0 {
+			status.CPU = time.Duration(ns)
+		}
+	}
+	if n, ok := service["TasksCurrent"].Value().(uint64); ok {
+		status.Tasks = counterValue(n)
+	}
+	if n, ok := service["NRestarts"].Value().(uint32); ok {
+		status.Restarts = int(n)
+	}
+	return status
+}
+
+// str returns a string property, or "" when it is missing.
+func str(props map[string]dbus.Variant, key string) string {
+	s, _ := props[key].Value().(string)
+	return s
+}
+
+// loadedUnit is one element of ListUnits' reply.
+type loadedUnit struct {
+	Name, Description, LoadState, ActiveState, SubState, Following string
+	Path                                                           dbus.ObjectPath
+	JobID                                                          uint32
+	JobType                                                        string
+	JobPath                                                        dbus.ObjectPath
+}
+
+// unitFile is one element of ListUnitFiles' reply.
+type unitFile struct {
+	Path, State string
+}
+
+func (m *dbusManager) List() ([]ServiceStatus, error) {
+	var loaded []loadedUnit
+	if err := m.manager().Call(systemdManager+".ListUnits", 0).Store(&loaded); err != nil {
+		return nil, err
+	}
+	files, err := m.unitFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	var units []listedUnit
+	paths := make(map[string]dbus.ObjectPath)
+	for _, u := range loaded {
+		if !strings.HasSuffix(u.Name, ".service") {
+			continue
+		}
+		units = append(units, listedUnit{Unit: u.Name, Load: u.LoadState, Active: u.ActiveState, Sub: u.SubState, Description: u.Description})
+		paths[strings.TrimSuffix(u.Name, ".service")] = u.Path
+	}
+	byName := mergeListings(units, files)
+	for name, s := range byName {
+		if s.Active != "active" {
+			continue
+		}
+		v, err := m.conn.Object(systemdDest, paths[name]).GetProperty(systemdService + ".MemoryCurrent")
+		if n, ok := v.Value().(uint64); err == nil && ok {
+			s.Memory = counterValue(n)
+		}
+	}
+	return sortedStatuses(byName), nil
+}
+
+func (m *dbusManager) unitFiles() ([]listedFile, error) {
+	var all []unitFile
+	if err := m.manager().Call(systemdManager+".ListUnitFiles", 0).Store(&all); err != nil {
+		return nil, err
+	}
+	var files []listedFile
+	for _, f := range all {
+		if name := path.Base(f.Path); strings.HasSuffix(name, ".service") {
+			files = append(files, listedFile{UnitFile: name, State: f.State})
+		}
+	}
+	return files, nil
+}
+
+func (m *dbusManager) Names() ([]string, error) {
+	files, err := m.unitFiles()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(files))
+	for i, f := range files {
+		names[i] = strings.TrimSuffix(f.UnitFile, ".service")
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Action asks systemd directly, letting polkit decide whether the user may.
+// When polkit wants a password but no authentication agent is running, it
+// falls back to sudo systemctl in the terminal.
+func (m *dbusManager) Action(name, action string) (*exec.Cmd, error) {
+	unit := unitName(name)
+	var err error
+	switch action {
+	case "start", "stop", "restart":
+		method := map[string]string{"start": "StartUnit", "stop": "StopUnit", "restart": "RestartUnit"}[action]
+		err = m.runJob(method, unit)
+	case "enable":
+		var carriesInstallInfo bool
+		var changes []struct{ Type, File, Dest string }
+		err = m.manager().Call(systemdManager+".EnableUnitFiles", dbus.FlagAllowInteractiveAuthorization,
+			[]string{unit}, false, false).Store(&carriesInstallInfo, &changes)
+		if err == nil {
+			err = m.reload()
+		}
+	case "disable":
+		var changes []struct{ Type, File, Dest string }
+		err = m.manager().Call(systemdManager+".DisableUnitFiles", dbus.FlagAllowInteractiveAuthorization,
+			[]string{unit}, false).Store(&changes)
+		if err == nil {
+			err = m.reload()
+		}
+	default:
+		return nil, fmt.Errorf("unknown service action %q", action)
+	}
+	if needsAuth(err) && m.scope == ScopeSystem {
+		return ServiceActionCmd(m.scope, name, action), nil
+	}
+	return nil, err
+}
+
+// needsAuth reports whether err is polkit refusing without asking.
+func needsAuth(err error) bool {
+	var dbusErr dbus.Error
+	if !errors.As(err, &dbusErr) {
+		return false
+	}
+	return dbusErr.Name == "org.freedesktop.DBus.Error.InteractiveAuthorizationRequired" ||
+		dbusErr.Name == "org.freedesktop.DBus.Error.AccessDenied"
+}
+
+func (m *dbusManager) reload() error {
+	return m.manager().Call(systemdManager+".Reload", dbus.FlagAllowInteractiveAuthorization).Err
+}
+
+// runJob queues a start, stop or restart job and waits for it to finish,
+// like systemctl does.
+func (m *dbusManager) runJob(method, unit string) error {
+	match := []dbus.MatchOption{
+		dbus.WithMatchInterface(systemdManager),
+		dbus.WithMatchMember("JobRemoved"),
+	}
+	if err := m.conn.AddMatchSignal(match...); err != nil {
+		return err
+	}
+	defer m.conn.RemoveMatchSignal(match...) //nolint:errcheck
+	signals := make(chan *dbus.Signal, 16)
+	m.conn.Signal(signals)
+	defer m.conn.RemoveSignal(signals)
+	m.subscribe()
+
+	var job dbus.ObjectPath
+	if err := m.manager().Call(systemdManager+"."+method, dbus.FlagAllowInteractiveAuthorization, unit, "replace").Store(&job); err != nil {
+		return err
+	}
+	timeout := time.After(jobTimeout)
+	for {
+		select {
+		case sig := <-signals:
+			// JobRemoved(u id, o job, s unit, s result)
+			if sig.Name != jobRemoved || len(sig.Body) < 4 || sig.Body[1] != job {
+				continue
+			}
+			if result, _ := sig.Body[3].(string); result != "done" {
+				return fmt.Errorf("job for %s %s", unit, result)
+			}
+			return nil
+		case <-timeout:
+			return fmt.Errorf("timed out waiting for %s", unit)
+		}
+	}
+}
+
+// subscribe asks systemd to emit job and unit signals. They go to every
+// client once one has subscribed; repeat calls are harmless.
+func (m *dbusManager) subscribe() {
+	m.manager().Call(systemdManager+".Subscribe", 0) //nolint:errcheck
+}
+
+func (m *dbusManager) Watch() (*Watch, error) {
+	match := []dbus.MatchOption{
+		dbus.WithMatchInterface(propertiesIface),
+		dbus.WithMatchMember("PropertiesChanged"),
+		dbus.WithMatchPathNamespace(dbus.ObjectPath(strings.TrimSuffix(unitPathPrefix, "/"))),
+	}
+	if err := m.conn.AddMatchSignal(match...); err != nil {
+		return nil, err
+	}
+	m.subscribe()
+	signals := make(chan *dbus.Signal, 64)
+	m.conn.Signal(signals)
+
+	out := make(chan string, 64)
+	done := make(chan struct{})
+	go func() {
+		defer close(out)
+		for {
+			select {
+			case sig := <-signals:
+				if sig.Name != propertiesChange || len(sig.Body) == 0 || sig.Body[0] != systemdUnit {
+					continue
+				}
+				unit, ok := unitFromPath(sig.Path)
+				if !ok || !strings.HasSuffix(unit, ".service") {
+					continue
+				}
+				select {
+				case out <- strings.TrimSuffix(unit, ".service"):
+				case <-done:
+					return
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+	return NewWatch(out, func() {
+		m.conn.RemoveSignal(signals)
+		m.conn.RemoveMatchSignal(match...) //nolint:errcheck
+		close(done)
+	}), nil
+}
+
+// unitFromPath decodes a unit's object path. systemd escapes every byte
+// that isn't a letter or digit as _xx ("sshd_2eservice").
+func unitFromPath(p dbus.ObjectPath) (string, bool) {
+	escaped, ok := strings.CutPrefix(string(p), unitPathPrefix)
+	if !ok {
+		return "", false
+	}
+	var b strings.Builder
+	for i := 0; i < len(escaped); i++ {
+		if escaped[i] != '_' {
+			b.WriteByte(escaped[i])
+			continue
+		}
+		if i+2 >= len(escaped) {
+			return "", false
+		}
+		n, err := strconv.ParseUint(escaped[i+1:i+3], 16, 8)
+		if err != nil {
+			return "", false
+		}
+		b.WriteByte(byte(n))
+		i += 2
+	}
+	return b.String(), true
+}
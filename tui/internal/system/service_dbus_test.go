@@ -0,0 +1,29 @@
+package system
+
+import (
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+)
+
+func TestUnitFromPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+		ok   bool
+	}{
+		{"/org/freedesktop/systemd1/unit/sshd_2eservice", "sshd.service", true},
+		{"/org/freedesktop/systemd1/unit/getty_40tty1_2eservice", "getty@tty1.service", true},
+		{"/org/freedesktop/systemd1/unit/systemd_2dlogind_2eservice", "systemd-logind.service", true},
+		{"/org/freedesktop/systemd1/unit/sshd_2", "", false},
+		{"/org/freedesktop/systemd1/unit/sshd_", "", false},
+		{"/org/freedesktop/systemd1/unit/sshd_xyservice", "", false},
+		{"/org/freedesktop/systemd1/job/42", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := unitFromPath(dbus.ObjectPath(tt.path))
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("unitFromPath(%q) = %q, %v, want %q, %v", tt.path, got, ok, tt.want, tt.ok)
+		}
+	}
+}
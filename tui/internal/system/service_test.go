@@ -0,0 +1,79 @@
+package system
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseShow(t *testing.T) {
+	tests := []struct {
+		name string
+		out  string
+		want ServiceStatus
+	}{
+		{
+			name: "missing.service",
+			out: "LoadState=not-found\nActiveState=inactive\nSubState=dead\nUnitFileState=\nMainPID=0\n" +
+				"Description=missing.service\nActiveEnterTimestamp=\nMemoryCurrent=[not set]\nCPUUsageNSec=[not set]\n" +
+				"TasksCurrent=[not set]\nNRestarts=0\nFragmentPath=\nResult=success\n",
+			want: ServiceStatus{Name: "missing.service", Active: "unknown", Enabled: "unknown", Memory: -1, CPU: -1, Tasks: -1},
+		},
+		{
+			name: "sshd.service",
+			out: "LoadState=loaded\nActiveState=active\nSubState=running\nUnitFileState=enabled\nMainPID=812\n" +
+				"Description=OpenSSH Daemon\nActiveEnterTimestamp=Tue 2024-03-05 10:00:00 UTC\nMemoryCurrent=4538368\n" +
+				"CPUUsageNSec=152000000\nTasksCurrent=1\nNRestarts=2\nFragmentPath=/usr/lib/systemd/system/sshd.service\n" +
+				"Result=success\n",
+			want: ServiceStatus{
+				Name: "sshd.service", Exists: true, Active: "active", Enabled: "enabled", PID: "812",
+				Description: "OpenSSH Daemon", SubState: "running",
+				Since:  time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC),
+				Memory: 4538368, CPU: 152 * time.Millisecond, Tasks: 1, Restarts: 2,
+				Path: "/usr/lib/systemd/system/sshd.service", Result: "success",
+			},
+		},
+		{
+			name: "backup.service",
+			out: "LoadState=loaded\nActiveState=failed\nSubState=failed\nUnitFileState=static\nMainPID=0\n" +
+				"Description=Nightly backup\nActiveEnterTimestamp=\nMemoryCurrent=18446744073709551615\n" +
+				"CPUUsageNSec=[not set]\nTasksCurrent=18446744073709551615\nNRestarts=5\n" +
+				"FragmentPath=/etc/systemd/system/backup.service\nResult=exit-code\n",
+			want: ServiceStatus{
+				Name: "backup.service", Exists: true, Active: "failed", Enabled: "static",
+				Description: "Nightly backup", SubState: "failed",
+				Memory: -1, CPU: -1, Tasks: -1, Restarts: 5,
+				Path: "/etc/systemd/system/backup.service", Result: "exit-code",
+			},
+		},
+	}
+	for _, tt := range tests {
+		got := parseShow(tt.name, []byte(tt.out))
+		if !got.Since.Equal(tt.want.Since) {
+			t.Errorf("%s: Since = %v, want %v", tt.name, got.Since, tt.want.Since)
+		}
+		got.Since, tt.want.Since = time.Time{}, time.Time{}
+		if got != tt.want {
+			t.Errorf("%s:\n got %+v\nwant %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCounter(t *testing.T) {
+	tests := []struct {
+		v    string
+		want int64
+	}{
+		{"4538368", 4538368},
+		{"0", 0},
+		{"[not set]", -1},
+		{"", -1},
+		{"18446744073709551615", -1},
+		{"9223372036854775808", -1},
+		{"9223372036854775807", 9223372036854775807},
+	}
+	for _, tt := range tests {
+		if got := counter(tt.v); got != tt.want {
+			t.Errorf("counter(%q) = %d, want %d", tt.v, got, tt.want)
+		}
+	}
+}
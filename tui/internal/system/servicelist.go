@@ -0,0 +1,156 @@
+package system
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// statusWorkers bounds concurrent lookups in FetchStatuses.
+const statusWorkers = 8
+
+// listedUnit is one element of `systemctl list-units --output=json`.
+type listedUnit struct {
+	Unit        string `json:"unit"`
+	Load        string `json:"load"`
+	Active      string `json:"active"`
+	Sub         string `json:"sub"`
+	Description string `json:"description"`
+}
+
+// listedFile is one element of `systemctl list-unit-files --output=json`.
+type listedFile struct {
+	UnitFile string `json:"unit_file"`
+	State    string `json:"state"`
+}
+
+// ListServiceStatuses returns every service in scope with its state, from
+// one list-units and one list-unit-files call, plus one show call for the
+// memory of running units. Other fields (PID, CPU, ...) are left unset. The
+// result is sorted by name. It fails on systemd versions without JSON output
+// (before 246); callers fall back to FetchStatuses.
+func ListServiceStatuses(scope Scope) ([]ServiceStatus, error) {
+	var units []listedUnit
+	if err := systemctlJSON(scope, &units, "list-units", "--type=service", "--all"); err != nil {
+		return nil, err
+	}
+	var files []listedFile
+	if err := systemctlJSON(scope, &files, "list-unit-files", "--type=service"); err != nil {
+		return nil, err
+	}
+
+	byName := mergeListings(units, files)
+	fillMemory(scope, byName)
+	return sortedStatuses(byName), nil
+}
+
+// mergeListings combines loaded units with installed unit files. Unit files
+// that aren't loaded are inactive; loaded units without a file (transient or
+// generated) are kept.
+func mergeListings(units []listedUnit, files []listedFile) map[string]*ServiceStatus {
+	byName := make(map[string]*ServiceStatus, len(files))
+	get := func(unit string) *ServiceStatus {
+		name := strings.TrimSuffix(unit, ".service")
+		s, ok := byName[name]
+		if !ok {
+			s = &ServiceStatus{
+				Name: name, Exists: true, Active: "inactive", SubState: "dead", Enabled: "unknown",
+				Memory: -1, CPU: -1, Tasks: -1,
+			}
+			byName[name] = s
+		}
+		return s
+	}
+	for _, f := range files {
+		get(f.UnitFile).Enabled = f.State
+	}
+	for _, u := range units {
+		if u.Load == "not-found" {
+			continue // Referenced by another unit but not installed
+		}
+		s := get(u.Unit)
+		s.Active, s.SubState, s.Description = u.Active, u.Sub, u.Description
+	}
+	return byName
+}
+
+func sortedStatuses(byName map[string]*ServiceStatus) []ServiceStatus {
+	statuses := make([]ServiceStatus, 0, len(byName))
+	for _, s := range byName {
+		statuses = append(statuses, *s)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses
+}
+
+// fillMemory reads MemoryCurrent for every active unit with one `systemctl
+// show`, which prints a block of properties per unit. Units it can't read
+// keep Memory at -1.
+func fillMemory(scope Scope, byName map[string]*ServiceStatus) {
+	var units []string
+	for name, s := range byName {
+		if s.Active == "active" {
+			units = append(units, name+".service")
+		}
+	}
+	if len(units) == 0 {
+		return
+	}
+	out, err := scope.systemctl(append([]string{"show", "--property=Id,MemoryCurrent"}, units...)...).Output()
+	if err != nil {
+		return
+	}
+	for _, block := range strings.Split(string(out), "\n\n") {
+		var id, mem string
+		for _, line := range strings.Split(block, "\n") {
+			switch k, v, _ := strings.Cut(line, "="); k {
+			case "Id":
+				id = v
+			case "MemoryCurrent":
+				mem = v
+			}
+		}
+		if s, ok := byName[strings.TrimSuffix(id, ".service")]; ok {
+			s.Memory = counter(mem)
+		}
+	}
+}
+
+func systemctlJSON(scope Scope, v any, args ...string) error {
+	out, err := scope.systemctl(append(args, "--output=json", "--no-pager")...).Output()
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(out, v); err != nil {
+		return fmt.Errorf("systemctl %s: %w", args[0], err)
+	}
+	return nil
+}
+
+// FetchStatuses looks up each service's status, a few at a time, sending
+// results in the order they finish. The channel is closed once all are done.
+func FetchStatuses(mgr Manager, names []string) <-chan ServiceStatus {
+	jobs := make(chan string)
+	out := make(chan ServiceStatus, statusWorkers)
+	var wg sync.WaitGroup
+	for range min(statusWorkers, len(names)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for name := range jobs {
+				out <- mgr.Status(name)
+			}
+		}()
+	}
+	go func() {
+		for _, name := range names {
+			jobs <- name
+		}
+		close(jobs)
+		wg.Wait()
+		close(out)
+	}()
+	return out
+}
@@ -0,0 +1,41 @@
+package system
+
+import "testing"
+
+func TestMergeListings(t *testing.T) {
+	units := []listedUnit{
+		{Unit: "sshd.service", Load: "loaded", Active: "active", Sub: "running", Description: "OpenSSH Daemon"},
+		{Unit: "run-u42.service", Load: "loaded", Active: "active", Sub: "exited", Description: "Transient"},
+		{Unit: "missing.service", Load: "not-found", Active: "inactive", Sub: "dead"},
+	}
+	files := []listedFile{
+		{UnitFile: "sshd.service", State: "enabled"},
+		{UnitFile: "cups.service", State: "disabled"},
+	}
+	byName := mergeListings(units, files)
+
+	want := map[string]ServiceStatus{
+		"sshd":    {Active: "active", SubState: "running", Enabled: "enabled", Description: "OpenSSH Daemon"},
+		"cups":    {Active: "inactive", SubState: "dead", Enabled: "disabled"},
+		"run-u42": {Active: "active", SubState: "exited", Enabled: "unknown", Description: "Transient"},
+	}
+	if len(byName) != len(want) {
+		t.Errorf("got %d services, want %d", len(byName), len(want))
+	}
+	for name, w := range want {
+		s, ok := byName[name]
+		if !ok {
+			t.Errorf("%s missing", name)
+			continue
+		}
+		if !s.Exists || s.Active != w.Active || s.SubState != w.SubState || s.Enabled != w.Enabled || s.Description != w.Description {
+			t.Errorf("%s = %+v, want %+v", name, *s, w)
+		}
+		if s.Memory != -1 || s.CPU != -1 || s.Tasks != -1 {
+			t.Errorf("%s counters = %d, %d, %d, want unset", name, s.Memory, s.CPU, s.Tasks)
+		}
+	}
+	if _, ok := byName["missing"]; ok {
+		t.Error("not-found unit was listed")
+	}
+}
@@ -0,0 +1,79 @@
+package system
+
+import (
+	"errors"
+	"os/exec"
+	"sync"
+	"sync/atomic"
+)
+
+// Manager controls the services of one scope. The Service Manager screens
+// go through it rather than a particular backend.
+type Manager interface {
+	// Status returns one service's status. Exists is false when there is no
+	// such unit.
+	Status(name string) ServiceStatus
+	// List returns every service with its state in one go, or an error when
+	// the backend can't; callers then use Names with FetchStatuses.
+	List() ([]ServiceStatus, error)
+	// Names returns the name of every installed service, sorted.
+	Names() ([]string, error)
+	// Action starts, stops, restarts, enables or disables a service. It
+	// returns a command to hand the terminal to when the action needs one
+	// (to ask for a sudo password), otherwise the action's result.
+	Action(name, action string) (*exec.Cmd, error)
+	// Watch reports services as their state changes, or ErrNoWatch.
+	Watch() (*Watch, error)
+}
+
+// ErrNoWatch is returned by backends that can't report state changes.
+var ErrNoWatch = errors.New("backend can't watch services")
+
+// NewManager returns the Manager for scope. backend is one of
+// config.ServiceBackends: "auto" talks to systemd over D-Bus when the bus is
+// reachable, falling back to systemctl.
+func NewManager(scope Scope, backend string) Manager {
+	if backend == "auto" {
+		if m, err := newDBusManager(scope); err == nil {
+			return m
+		}
+	}
+	return shellManager{scope: scope}
+}
+
+var nextWatchID atomic.Int64
+
+// Watch delivers the names of services whose state has changed.
+type Watch struct {
+	ID   int64
+	C    <-chan string // Closed once the watch is closed
+	once sync.Once
+	stop func()
+}
+
+// NewWatch returns a Watch delivering from c. stop is run once, on the
+// first Close, and must cause c to be closed.
+func NewWatch(c <-chan string, stop func()) *Watch {
+	return &Watch{ID: nextWatchID.Add(1), C: c, stop: stop}
+}
+
+// Close stops the watch. It is safe to call more than once.
+func (w *Watch) Close() { w.once.Do(w.stop) }
+
+// shellManager runs systemctl and parses its output. Actions on system
+// units go through sudo in the terminal.
+type shellManager struct {
+	scope Scope
+}
+
+func (m shellManager) Status(name string) ServiceStatus { return GetServiceStatus(m.scope, name) }
+
+func (m shellManager) List() ([]ServiceStatus, error) { return ListServiceStatuses(m.scope) }
+
+func (m shellManager) Names() ([]string, error) { return ListAllServices(m.scope) }
+
+func (m shellManager) Action(name, action string) (*exec.Cmd, error) {
+	return ServiceActionCmd(m.scope, name, action), nil
+}
+
+func (m shellManager) Watch() (*Watch, error) { return nil, ErrNoWatch }
@@ -0,0 +1,124 @@
+// Package systemtest provides an in-memory system.Manager for tests.
+package systemtest
+
+import (
+	"fmt"
+	"os/exec"
+	"sort"
+	"sync"
+
+	"github.com/reisset/mypctools/tui/internal/system"
+)
+
+// Fake is an in-memory system.Manager. Actions change the services it
+// holds and are reported to its watches.
+type Fake struct {
+	mu       sync.Mutex
+	services map[string]system.ServiceStatus
+	watches  map[*system.Watch]chan string
+	Actions  []string // "start sshd", in the order they were run
+	Err      error    // Returned by every action when set
+}
+
+var _ system.Manager = (*Fake)(nil)
+
+// NewFake returns a Fake holding services.
+func NewFake(services ...system.ServiceStatus) *Fake {
+	f := &Fake{services: make(map[string]system.ServiceStatus), watches: make(map[*system.Watch]chan string)}
+	for _, s := range services {
+		f.Set(s)
+	}
+	return f
+}
+
+// Set adds or replaces a service, as if its state had changed on its own.
+func (f *Fake) Set(s system.ServiceStatus) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	s.Exists = true
+	f.services[s.Name] = s
+	f.notify(s.Name)
+}
+
+// notify tells every watch that name changed. Watches that aren't keeping
+// up miss the change. f.mu must be held.
+func (f *Fake) notify(name string) {
+	for _, c := range f.watches {
+		select {
+		case c <- name:
+		default:
+		}
+	}
+}
+
+func (f *Fake) Status(name string) system.ServiceStatus {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if s, ok := f.services[name]; ok {
+		return s
+	}
+	return system.ServiceStatus{Name: name, Active: "unknown", Enabled: "unknown", Memory: -1, CPU: -1, Tasks: -1}
+}
+
+func (f *Fake) List() ([]system.ServiceStatus, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	statuses := make([]system.ServiceStatus, 0, len(f.services))
+	for _, s := range f.services {
+		statuses = append(statuses, s)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses, nil
+}
+
+func (f *Fake) Names() ([]string, error) {
+	statuses, _ := f.List()
+	names := make([]string, len(statuses))
+	for i, s := range statuses {
+		names[i] = s.Name
+	}
+	return names, nil
+}
+
+func (f *Fake) Action(name, action string) (*exec.Cmd, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.Actions = append(f.Actions, action+" "+name)
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	s, ok := f.services[name]
+	if !ok {
+		return nil, fmt.Errorf("unit %s.service not found", name)
+	}
+	switch action {
+	case "start", "restart":
+		s.Active, s.SubState = "active", "running"
+	case "stop":
+		s.Active, s.SubState = "inactive", "dead"
+	case "enable":
+		s.Enabled = "enabled"
+	case "disable":
+		s.Enabled = "disabled"
+	default:
+		return nil, fmt.Errorf("unknown service action %q", action)
+	}
+	f.services[name] = s
+	f.notify(name)
+	return nil, nil
+}
+
+func (f *Fake) Watch() (*system.Watch, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	c := make(chan string, 64)
+	var w *system.Watch
+	w = system.NewWatch(c, func() {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		delete(f.watches, w)
+		close(c)
+	})
+	f.watches[w] = c
+	return w, nil
+}
@@ -1,9 +1,6 @@
 package theme
 
-import (
-	"os"
-	"sync"
-)
+import "sync"
 
 var iconsMu sync.RWMutex
 
@@ -74,19 +71,12 @@ var ASCIIIcons = IconSet{
 // Icons is the active icon set.
 var Icons IconSet
 
-const nerdFontFlagPath = ".config/mypctools/nerd-font"
-
-// InitIcons reads the Nerd Font preference flag and sets Icons.
-// Call once at startup.
-func InitIcons() {
+// SetNerdIcons switches between Nerd Font and ASCII icons. The preference
+// itself lives in config.toml.
+func SetNerdIcons(on bool) {
 	iconsMu.Lock()
 	defer iconsMu.Unlock()
-	home, err := os.UserHomeDir()
-	if err != nil {
-		Icons = ASCIIIcons
-		return
-	}
-	if _, err := os.Stat(home + "/" + nerdFontFlagPath); err == nil {
+	if on {
 		Icons = NerdIcons
 	} else {
 		Icons = ASCIIIcons
@@ -106,23 +96,3 @@ func GetIcons() IconSet {
 	defer iconsMu.RUnlock()
 	return Icons
 }
-
-// ToggleIconSet flips between Nerd Font and ASCII icons and persists the choice.
-func ToggleIconSet() {
-	iconsMu.Lock()
-	defer iconsMu.Unlock()
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return
-	}
-	flagPath := home + "/" + nerdFontFlagPath
-
-	if Icons == NerdIcons {
-		Icons = ASCIIIcons
-		os.Remove(flagPath)
-	} else {
-		Icons = NerdIcons
-		os.MkdirAll(home+"/.config/mypctools", 0755)
-		os.WriteFile(flagPath, nil, 0644)
-	}
-}
@@ -2,6 +2,7 @@ package ui
 
 import (
 	"github.com/charmbracelet/lipgloss"
+	"github.com/reisset/mypctools/tui/internal/bundle"
 	"github.com/reisset/mypctools/tui/internal/theme"
 )
 
@@ -11,3 +12,31 @@ func InstalledBadge() string {
 		Foreground(lipgloss.Color(theme.Current.Success)).
 		Render("✓ installed")
 }
+
+// PartialBadge returns an amber "◐ partial" indicator for half-installed bundles.
+func PartialBadge() string {
+	return lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.Current.Warning)).
+		Render("◐ partial")
+}
+
+// DriftedBadge returns an amber "≠ drifted" indicator for bundles whose files
+// were changed outside mypctools.
+func DriftedBadge() string {
+	return lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.Current.Warning)).
+		Render("≠ drifted")
+}
+
+// BundleBadge returns the badge for a detected bundle state, or "" when missing.
+func BundleBadge(st bundle.State) string {
+	switch st {
+	case bundle.StateInstalled:
+		return InstalledBadge()
+	case bundle.StatePartial:
+		return PartialBadge()
+	case bundle.StateDrifted:
+		return DriftedBadge()
+	}
+	return ""
+}
@@ -7,12 +7,17 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/reisset/mypctools/tui/internal/app"
+	"github.com/reisset/mypctools/tui/internal/bundle"
+	"github.com/reisset/mypctools/tui/internal/cli"
 	"github.com/reisset/mypctools/tui/internal/cmd"
 	"github.com/reisset/mypctools/tui/internal/config"
+	"github.com/reisset/mypctools/tui/internal/fssnap"
+	"github.com/reisset/mypctools/tui/internal/pkgmgr"
+	"github.com/reisset/mypctools/tui/internal/runner"
 	"github.com/reisset/mypctools/tui/internal/screen/mainmenu"
+	"github.com/reisset/mypctools/tui/internal/screen/settings"
 	"github.com/reisset/mypctools/tui/internal/selfupdate"
 	"github.com/reisset/mypctools/tui/internal/state"
-	"github.com/reisset/mypctools/tui/internal/theme"
 )
 
 func main() {
@@ -28,6 +33,7 @@ func main() {
 			fmt.Println()
 			fmt.Println("Commands:")
 			fmt.Println("  update           Update binary and scripts to latest version")
+			fmt.Println("  bundle           Headless bundle management (run 'mypctools bundle --help')")
 			fmt.Println()
 			fmt.Println("Options:")
 			fmt.Println("  --help, -h       Show this help message")
@@ -38,13 +44,31 @@ func main() {
 			os.Exit(0)
 		case "update":
 			scriptsDir := findRootDir()
+			// A broken config must not keep the user from updating to a fix
+			cfg, err := config.Load()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: invalid config, using defaults:\n%v\n\n", err)
+				cfg = config.Default()
+			}
+			u := cfg.Update
 			fmt.Println("Updating mypctools...")
-			if err := selfupdate.Update(scriptsDir); err != nil {
+			if err := selfupdate.Update(scriptsDir, u.Remote, u.Branch); err != nil {
 				fmt.Fprintf(os.Stderr, "Update failed: %v\n", err)
 				os.Exit(1)
 			}
 			fmt.Println("\nUpdate complete! Run 'mypctools' to start.")
 			os.Exit(0)
+		case "bundle":
+			if os.Geteuid() == 0 {
+				fmt.Fprintln(os.Stderr, "Do not run as root. Use your normal user.")
+				os.Exit(1)
+			}
+			rootDir := findRootDir()
+			if err := bundle.Load(rootDir); err != nil {
+				fmt.Fprintf(os.Stderr, "Could not load bundles: %v\n", err)
+				os.Exit(1)
+			}
+			os.Exit(cli.RunBundle(rootDir, os.Args[2:], os.Stdout, os.Stderr))
 		default:
 			fmt.Fprintf(os.Stderr, "Unknown option: %s\nRun 'mypctools --help' for usage.\n", os.Args[1])
 			os.Exit(1)
@@ -57,18 +81,29 @@ func main() {
 		os.Exit(1)
 	}
 
-	// Init icon set from saved preference (no fc-list scan)
-	theme.InitIcons()
+	// Load config.toml (icons, output capture, ...) before anything renders
+	cfg := loadConfig()
+	settings.Apply(cfg)
 
 	// Find the mypctools root directory (parent of tui/)
 	rootDir := findRootDir()
 
+	// Discover bundle manifests under scripts/. Invalid ones are listed on
+	// the scripts screen; the rest still load.
+	if err := bundle.Load(rootDir); err != nil {
+		fmt.Fprintf(os.Stderr, "Could not load bundles: %v\n", err)
+		os.Exit(1)
+	}
+
 	// Detect distro
 	distro := cmd.DetectDistro()
 
 	// Build shared state
 	shared := &state.Shared{
 		Distro:  distro,
+		Config:  cfg,
+		Pkg:     pkgmgr.For(distro),
+		Snap:    fssnap.Detect(),
 		RootDir: rootDir,
 	}
 
@@ -88,16 +123,33 @@ func main() {
 				fmt.Fprintf(os.Stderr, "Background update check panicked: %v\n", r)
 			}
 		}()
-		result := state.CheckForUpdates(rootDir)()
+		result := state.CheckForUpdates(rootDir, cfg.Update)()
 		p.Send(result)
 	}()
 
-	if _, err := p.Run(); err != nil {
+	_, err := p.Run()
+	runner.StopAll() // Don't leave captured commands running after a quit
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 }
 
+// loadConfig reads config.toml, exiting when it is invalid so a typo is
+// not silently replaced by defaults on the next save. A failed flag file
+// migration only warns; it is retried next run.
+func loadConfig() config.File {
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid config:\n%v\n", err)
+		os.Exit(1)
+	}
+	if err := config.MigrateFlags(&cfg); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	return cfg
+}
+
 // findRootDir locates the mypctools repo root.
 // It walks up from the executable path looking for scripts/ directory.
 func findRootDir() string {